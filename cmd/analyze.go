@@ -15,39 +15,176 @@ import (
 
 var fromStdin bool
 
+// PasswordReport is the structured result of analyzing one password. The
+// password itself is never included.
+type PasswordReport struct {
+	Line      int      `json:"line,omitempty"`
+	Length    int      `json:"length"`
+	Classes   int      `json:"classes"`
+	Entropy   float64  `json:"entropy_bits_per_char"`
+	Verdict   string   `json:"verdict"`
+	Warnings  []string `json:"warnings,omitempty"`
+	Reference string   `json:"reference"`
+}
+
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze [password]",
 	Short: "Analyze a password (offline entropy/heuristics; AI later)",
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Analyze a password offline for length, character classes, entropy and
+common patterns.
+
+--file analyzes one password per line ("-" for STDIN). Batch mode and the
+json, sarif and junit formats fail if any password is weak, so the command
+can run as a policy check in CI.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var pwd string
-		if fromStdin {
-			info, _ := os.Stdin.Stat()
-			if (info.Mode() & os.ModeCharDevice) != 0 {
-				return fmt.Errorf("--stdin provided but no piped input")
-			}
-			s := bufio.NewScanner(os.Stdin)
-			if s.Scan() {
-				pwd = strings.TrimSpace(s.Text())
+		file, _ := cmd.Flags().GetString("file")
+		format, err := getReportFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		var reports []PasswordReport
+		if file != "" {
+			if reports, err = analyzePasswordFile(file); err != nil {
+				return err
 			}
-		} else if len(args) == 1 {
-			pwd = args[0]
 		} else {
-			return fmt.Errorf("provide a password or use --stdin")
+			pwd, err := readSecretArg(cmd, args, "password")
+			if err != nil {
+				return err
+			}
+			if format == "text" {
+				fmt.Println(analyzePassword(pwd))
+				return nil
+			}
+			reports = []PasswordReport{passwordReport(pwd)}
 		}
-
-		report := analyzePassword(pwd)
-		fmt.Println(report)
-		return nil
+		return printPasswordReports(reports, format, file)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(analyzeCmd)
 	analyzeCmd.Flags().BoolVar(&fromStdin, "stdin", false, "read password from STDIN")
+	analyzeCmd.Flags().StringP("file", "f", "", "analyze one password per line from this file (- for STDIN)")
+	addReportFormatFlag(analyzeCmd)
+}
+
+// analyzePasswordFile analyzes every non-empty line of a password list
+func analyzePasswordFile(name string) ([]PasswordReport, error) {
+	in := os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open password file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var reports []PasswordReport
+	s := bufio.NewScanner(in)
+	for n := 1; s.Scan(); n++ {
+		if pwd := strings.TrimRight(s.Text(), "\r"); pwd != "" {
+			r := passwordReport(pwd)
+			r.Line = n
+			reports = append(reports, r)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read passwords: %w", err)
+	}
+	if len(reports) == 0 {
+		return nil, fmt.Errorf("no passwords found")
+	}
+	return reports, nil
+}
+
+// printPasswordReports outputs batch results and fails if any are weak
+func printPasswordReports(reports []PasswordReport, format, file string) error {
+	results := make([]checkResult, 0, len(reports))
+	weak := 0
+	for i, r := range reports {
+		name := fmt.Sprintf("password %d", i+1)
+		if r.Line > 0 {
+			name = fmt.Sprintf("line %d", r.Line)
+		}
+		msg := fmt.Sprintf("%s password (length %d, %d classes, %.2f bits/char)", r.Verdict, r.Length, r.Classes, r.Entropy)
+		if len(r.Warnings) > 0 {
+			msg += ": " + strings.Join(r.Warnings, " ")
+		}
+		res := checkResult{Name: name, Rule: "password-weak", Passed: r.Verdict != "Weak", Message: msg}
+		if file != "" && file != "-" {
+			res.Path, res.Line = file, r.Line
+		}
+		if !res.Passed {
+			weak++
+		}
+		results = append(results, res)
+	}
+
+	var err error
+	switch format {
+	case "json":
+		err = printJSON(os.Stdout, reports, "password report")
+	case "sarif":
+		err = printSARIF(results, []sarifRule{{ID: "password-weak", ShortDescription: sarifMessage{Text: "Weak password"}}})
+	case "junit":
+		err = printJUnit("keyforge analyze", results)
+	default:
+		for _, r := range results {
+			fmt.Printf("%s: %s\n", r.Name, r.Message)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if weak > 0 {
+		return fmt.Errorf("%d of %d passwords are weak", weak, len(reports))
+	}
+	return nil
+}
+
+// readSecretArg returns the secret from the first argument or, with --stdin,
+// from the first line of piped input
+func readSecretArg(cmd *cobra.Command, args []string, what string) (string, error) {
+	useStdin, _ := cmd.Flags().GetBool("stdin")
+	if useStdin {
+		info, _ := os.Stdin.Stat()
+		if (info.Mode() & os.ModeCharDevice) != 0 {
+			return "", fmt.Errorf("--stdin provided but no piped input")
+		}
+		s := bufio.NewScanner(os.Stdin)
+		if s.Scan() {
+			return strings.TrimSpace(s.Text()), nil
+		}
+		return "", s.Err()
+	}
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return "", fmt.Errorf("provide a %s or use --stdin", what)
 }
 
 func analyzePassword(p string) string {
+	r := passwordReport(p)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Length: %d\n", r.Length)
+	fmt.Fprintf(&b, "Classes: %d (lower/upper/digit/symbol)\n", r.Classes)
+	fmt.Fprintf(&b, "Entropy: %.2f bits/char\n", r.Entropy)
+	fmt.Fprintf(&b, "Verdict: %s\n", r.Verdict)
+	if len(r.Warnings) > 0 {
+		fmt.Fprintf(&b, "Warnings:\n  - %s\n", strings.Join(r.Warnings, "\n  - "))
+	}
+	fmt.Fprintf(&b, "Reference: sha256(...)=...%s\n", r.Reference)
+	return b.String()
+}
+
+// passwordReport computes the verdict and warnings for p
+func passwordReport(p string) PasswordReport {
 	length := len(p)
 	classes := charClasses(p)
 	entropy := shannonEntropy(p)
@@ -77,16 +214,14 @@ func analyzePassword(p string) string {
 	hash := sha256.Sum256([]byte(p))
 	hashTail := fmt.Sprintf("%x", hash)[56:]
 
-	var b strings.Builder
-	fmt.Fprintf(&b, "Length: %d\n", length)
-	fmt.Fprintf(&b, "Classes: %d (lower/upper/digit/symbol)\n", classes)
-	fmt.Fprintf(&b, "Entropy: %.2f bits/char\n", entropy)
-	fmt.Fprintf(&b, "Verdict: %s\n", verdict)
-	if len(warnings) > 0 {
-		fmt.Fprintf(&b, "Warnings:\n  - %s\n", strings.Join(warnings, "\n  - "))
-	}
-	fmt.Fprintf(&b, "Reference: sha256(...)=...%s\n", hashTail)
-	return b.String()
+	return PasswordReport{
+		Length:    length,
+		Classes:   classes,
+		Entropy:   entropy,
+		Verdict:   verdict,
+		Warnings:  warnings,
+		Reference: hashTail,
+	}
 }
 
 func shannonEntropy(s string) float64 {
@@ -0,0 +1,496 @@
+// cmd/analyze_hash.go
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Storage ratings, from best to worst. locked and unknown are informational.
+const (
+	ratingStrong     = "strong"
+	ratingAcceptable = "acceptable"
+	ratingWeak       = "weak"
+	ratingBroken     = "broken"
+	ratingLocked     = "locked"
+	ratingUnknown    = "unknown"
+)
+
+// Minimum work factors, following the OWASP Password Storage Cheat Sheet;
+// SHA-crypt uses the passlib defaults since OWASP gives no figure
+const (
+	minBcryptCost        = 10
+	goodBcryptCost       = 12
+	minArgon2MemTime     = 7168 * 5
+	minArgon2MemoryKiB   = 7168
+	minScryptWork        = (1 << 17) * 8
+	minPBKDF2SHA1Rounds  = 1300000
+	minPBKDF2SHA256      = 600000
+	minPBKDF2SHA512      = 210000
+	minSHA256CryptRounds = 535000
+	minSHA512CryptRounds = 656000
+	minSCRAMIterations   = 4096
+)
+
+var (
+	hexHashRe  = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	desCryptRe = regexp.MustCompile(`^[./0-9A-Za-z]{13}$`)
+)
+
+// HashReport describes how a single stored password hash is protected
+type HashReport struct {
+	User       string   `json:"user,omitempty"`
+	Line       int      `json:"line,omitempty"`
+	Algorithm  string   `json:"algorithm"`
+	Parameters string   `json:"parameters,omitempty"`
+	Rating     string   `json:"rating"`
+	Issues     []string `json:"issues,omitempty"`
+}
+
+var analyzeHashCmd = &cobra.Command{
+	Use:   "hash [hash]",
+	Short: "Identify a password hash and rate its storage scheme",
+	Long: `Identify the algorithm of a stored password hash (crypt(3), PHC strings,
+LDAP userPassword, database formats, NTLM and unsalted digests) and rate it
+against current recommendations, flagging weak parameters.
+
+--file reads a shadow, htpasswd, pwdump or one-hash-per-line file and
+--stdin reads the same from piped input. The command fails if any hash is
+rated weak or broken, so it can gate an audit.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		useStdin, _ := cmd.Flags().GetBool("stdin")
+		format, err := getReportFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		var reports []HashReport
+		switch {
+		case file != "":
+			f, err := os.Open(file)
+			if err != nil {
+				return fmt.Errorf("failed to open hash file: %w", err)
+			}
+			defer f.Close()
+			if reports, err = analyzeHashLines(f); err != nil {
+				return err
+			}
+		case useStdin:
+			if reports, err = analyzeHashLines(os.Stdin); err != nil {
+				return err
+			}
+		case len(args) == 1:
+			reports = []HashReport{analyzeHashLine(args[0])}
+		default:
+			return fmt.Errorf("provide a hash, --file or --stdin")
+		}
+		if len(reports) == 0 {
+			return fmt.Errorf("no hashes found")
+		}
+
+		switch format {
+		case "json":
+			err = printJSON(os.Stdout, reports, "hash report")
+		case "sarif":
+			err = printSARIF(hashCheckResults(reports, file), ratingRules("hash", "password hash storage"))
+		case "junit":
+			err = printJUnit("keyforge analyze hash", hashCheckResults(reports, file))
+		default:
+			printHashReports(reports)
+		}
+		if err != nil {
+			return err
+		}
+
+		bad := 0
+		for _, r := range reports {
+			if failedRating(r.Rating) {
+				bad++
+			}
+		}
+		if bad > 0 {
+			return fmt.Errorf("%d of %d hashes use weak or broken storage", bad, len(reports))
+		}
+		return nil
+	},
+}
+
+// analyzeHashLines analyzes every non-comment line of a credential file
+func analyzeHashLines(r io.Reader) ([]HashReport, error) {
+	var reports []HashReport
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rep := analyzeHashLine(line)
+		rep.Line = n
+		reports = append(reports, rep)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read hashes: %w", err)
+	}
+	return reports, nil
+}
+
+// analyzeHashLine analyzes a bare hash or a shadow (user:hash:...), htpasswd
+// (user:hash) or pwdump (user:rid:LM:NT:::) line
+func analyzeHashLine(line string) HashReport {
+	if strings.HasPrefix(line, "SCRAM-") || !strings.Contains(line, ":") {
+		return analyzeHash(line)
+	}
+	fields := strings.Split(line, ":")
+	var r HashReport
+	if len(fields) >= 4 && isHexLen(fields[2], 32) && isHexLen(fields[3], 32) {
+		r = HashReport{Algorithm: "NTLM", Rating: ratingBroken, Issues: []string{"unsalted MD4; crackable at GPU speed and usable for pass-the-hash"}}
+	} else {
+		r = analyzeHash(fields[1])
+	}
+	r.User = fields[0]
+	return r
+}
+
+// analyzeHash identifies h and rates it
+func analyzeHash(h string) HashReport {
+	// Shadow lock markers and empty passwords
+	switch {
+	case h == "":
+		return HashReport{Algorithm: "none", Rating: ratingBroken, Issues: []string{"empty password field allows login without a password"}}
+	case h == "*" || h == "!" || h == "!!" || h == "*LK*":
+		return HashReport{Algorithm: "none", Rating: ratingLocked}
+	case strings.HasPrefix(h, "!"):
+		r := analyzeHash(strings.TrimLeft(h, "!"))
+		r.Issues = append(r.Issues, "account is locked; the hash is still exposed")
+		return r
+	}
+
+	switch {
+	case strings.HasPrefix(h, "{"):
+		return analyzeLDAPHash(h)
+	case strings.HasPrefix(h, "SCRAM-SHA-256$"):
+		return analyzeSCRAMHash(h)
+	case strings.HasPrefix(h, "$"):
+		return analyzeCryptHash(h)
+	case strings.HasPrefix(h, "*") && isHexLen(h[1:], 40):
+		return HashReport{Algorithm: "mysql_native_password", Rating: ratingBroken, Issues: []string{"unsalted double SHA-1; switch to caching_sha2_password"}}
+	case strings.HasPrefix(h, "md5") && isHexLen(h[3:], 32):
+		return HashReport{Algorithm: "PostgreSQL MD5", Rating: ratingBroken, Issues: []string{"MD5 salted only with the user name; switch to SCRAM-SHA-256"}}
+	case isHexLen(h, 32):
+		return HashReport{Algorithm: "MD5 or NTLM", Rating: ratingBroken, Issues: []string{"unsalted fast digest"}}
+	case isHexLen(h, 40):
+		return HashReport{Algorithm: "SHA-1", Rating: ratingBroken, Issues: []string{"unsalted fast digest"}}
+	case isHexLen(h, 64):
+		return HashReport{Algorithm: "SHA-256", Rating: ratingBroken, Issues: []string{"unsalted fast digest"}}
+	case isHexLen(h, 128):
+		return HashReport{Algorithm: "SHA-512", Rating: ratingBroken, Issues: []string{"unsalted fast digest"}}
+	case desCryptRe.MatchString(h):
+		return HashReport{Algorithm: "DES crypt", Rating: ratingBroken, Issues: []string{"only the first 8 characters are used; 12-bit salt"}}
+	}
+	return HashReport{Algorithm: "unrecognized", Rating: ratingUnknown}
+}
+
+// analyzeCryptHash handles $id$ crypt(3) and PHC strings
+func analyzeCryptHash(h string) HashReport {
+	parts := strings.Split(h, "$")
+	id := parts[1]
+	switch id {
+	case "1":
+		return HashReport{Algorithm: "md5-crypt", Rating: ratingWeak, Issues: []string{"1000 fixed MD5 iterations; fast on GPUs"}}
+	case "apr1":
+		return HashReport{Algorithm: "apr1 (Apache MD5)", Rating: ratingWeak, Issues: []string{"1000 fixed MD5 iterations; use htpasswd -B (bcrypt)"}}
+	case "2", "2a", "2b", "2x", "2y":
+		return analyzeBcryptHash(id, parts)
+	case "5", "6":
+		return analyzeSHACryptHash(id, parts)
+	case "y", "gy":
+		return HashReport{Algorithm: "yescrypt", Rating: ratingStrong}
+	case "7":
+		return HashReport{Algorithm: "scrypt (crypt)", Rating: ratingStrong}
+	case "argon2id", "argon2i", "argon2d":
+		return analyzeArgon2Hash(id, parts)
+	case "scrypt":
+		return analyzeScryptHash(parts)
+	case "pbkdf2", "pbkdf2-sha256", "pbkdf2-sha512":
+		return analyzePBKDF2Hash(id, parts)
+	case "A":
+		r := analyzeSHACryptHash("5", []string{"", "5", "rounds=" + mysqlRounds(parts)})
+		r.Algorithm = "caching_sha2_password (MySQL)"
+		return r
+	}
+	return HashReport{Algorithm: "crypt $" + id + "$", Rating: ratingUnknown}
+}
+
+func analyzeBcryptHash(id string, parts []string) HashReport {
+	r := HashReport{Algorithm: "bcrypt"}
+	if len(parts) < 4 {
+		r.Rating = ratingUnknown
+		r.Issues = []string{"malformed bcrypt hash"}
+		return r
+	}
+	cost, _ := strconv.Atoi(parts[2])
+	r.Parameters = fmt.Sprintf("cost=%d", cost)
+	switch {
+	case cost < minBcryptCost:
+		r.Rating = ratingWeak
+		r.Issues = append(r.Issues, fmt.Sprintf("cost %d is below the minimum of %d", cost, minBcryptCost))
+	case cost < goodBcryptCost:
+		r.Rating = ratingAcceptable
+		r.Issues = append(r.Issues, fmt.Sprintf("consider cost %d or higher", goodBcryptCost))
+	default:
+		r.Rating = ratingStrong
+	}
+	if id == "2" || id == "2x" {
+		r.Rating = ratingWeak
+		r.Issues = append(r.Issues, "$"+id+"$ is a legacy or bug-compatible bcrypt variant")
+	}
+	return r
+}
+
+func analyzeSHACryptHash(id string, parts []string) HashReport {
+	r := HashReport{Algorithm: "sha512-crypt"}
+	minRounds := minSHA512CryptRounds
+	if id == "5" {
+		r.Algorithm, minRounds = "sha256-crypt", minSHA256CryptRounds
+	}
+	rounds := shaCryptRoundsDef
+	if len(parts) > 2 && strings.HasPrefix(parts[2], "rounds=") {
+		rounds, _ = strconv.Atoi(strings.TrimPrefix(parts[2], "rounds="))
+	}
+	r.Parameters = fmt.Sprintf("rounds=%d", rounds)
+	if rounds < minRounds {
+		r.Rating = ratingWeak
+		r.Issues = append(r.Issues, fmt.Sprintf("%d rounds is below the recommended %d", rounds, minRounds))
+	} else {
+		r.Rating = ratingAcceptable
+	}
+	r.Issues = append(r.Issues, "not memory-hard; prefer yescrypt, argon2id or bcrypt")
+	return r
+}
+
+func analyzeArgon2Hash(id string, parts []string) HashReport {
+	r := HashReport{Algorithm: id}
+	var params map[string]int
+	for _, p := range parts[2:] {
+		if strings.Contains(p, "m=") {
+			params = parsePHCParams(p)
+		}
+	}
+	if params == nil {
+		r.Rating = ratingUnknown
+		r.Issues = []string{"missing argon2 parameters"}
+		return r
+	}
+	m, t, p := params["m"], params["t"], params["p"]
+	r.Parameters = fmt.Sprintf("m=%d,t=%d,p=%d", m, t, p)
+	if m < minArgon2MemoryKiB || m*t < minArgon2MemTime {
+		r.Rating = ratingWeak
+		r.Issues = append(r.Issues, fmt.Sprintf("memory x iterations (%d KiB x %d) is below the OWASP minimum", m, t))
+	} else {
+		r.Rating = ratingStrong
+	}
+	if id != "argon2id" {
+		if r.Rating == ratingStrong {
+			r.Rating = ratingAcceptable
+		}
+		r.Issues = append(r.Issues, "argon2id is the recommended variant")
+	}
+	return r
+}
+
+func analyzeScryptHash(parts []string) HashReport {
+	r := HashReport{Algorithm: "scrypt"}
+	if len(parts) < 3 {
+		r.Rating = ratingUnknown
+		return r
+	}
+	params := parsePHCParams(parts[2])
+	ln, rr, p := params["ln"], params["r"], params["p"]
+	r.Parameters = fmt.Sprintf("ln=%d,r=%d,p=%d", ln, rr, p)
+	if ln <= 0 || ln > 40 || (1<<ln)*rr*p < minScryptWork {
+		r.Rating = ratingWeak
+		r.Issues = append(r.Issues, "work factor is below N=2^17, r=8, p=1")
+	} else {
+		r.Rating = ratingStrong
+	}
+	return r
+}
+
+func analyzePBKDF2Hash(id string, parts []string) HashReport {
+	r := HashReport{Algorithm: id}
+	minRounds := minPBKDF2SHA1Rounds
+	switch id {
+	case "pbkdf2":
+		r.Algorithm = "pbkdf2-sha1"
+	case "pbkdf2-sha256":
+		minRounds = minPBKDF2SHA256
+	case "pbkdf2-sha512":
+		minRounds = minPBKDF2SHA512
+	}
+	if len(parts) < 3 {
+		r.Rating = ratingUnknown
+		return r
+	}
+	// PHC uses i=N,l=N; passlib puts the bare round count in this field
+	rounds, err := strconv.Atoi(parts[2])
+	if err != nil {
+		rounds = parsePHCParams(parts[2])["i"]
+	}
+	r.Parameters = fmt.Sprintf("i=%d", rounds)
+	if rounds < minRounds {
+		r.Rating = ratingWeak
+		r.Issues = append(r.Issues, fmt.Sprintf("%d iterations is below the recommended %d", rounds, minRounds))
+	} else {
+		r.Rating = ratingAcceptable
+	}
+	r.Issues = append(r.Issues, "not memory-hard; prefer argon2id or scrypt")
+	return r
+}
+
+func analyzeLDAPHash(h string) HashReport {
+	end := strings.Index(h, "}")
+	if end < 0 {
+		return HashReport{Algorithm: "unrecognized", Rating: ratingUnknown}
+	}
+	scheme, rest := strings.ToUpper(h[1:end]), h[end+1:]
+	switch scheme {
+	case "CRYPT":
+		r := analyzeHash(rest)
+		r.Algorithm = "LDAP {CRYPT} " + r.Algorithm
+		return r
+	case "SSHA", "SSHA256", "SSHA512", "SMD5":
+		return HashReport{Algorithm: "LDAP {" + scheme + "}", Rating: ratingWeak, Issues: []string{"single salted fast digest; use {CRYPT} with a slow scheme or an argon2 overlay"}}
+	case "SHA", "SHA256", "SHA512", "MD5":
+		return HashReport{Algorithm: "LDAP {" + scheme + "}", Rating: ratingBroken, Issues: []string{"unsalted fast digest"}}
+	case "CLEARTEXT", "PLAIN":
+		return HashReport{Algorithm: "LDAP {" + scheme + "}", Rating: ratingBroken, Issues: []string{"password stored in plaintext"}}
+	case "ARGON2":
+		r := analyzeHash(rest)
+		r.Algorithm = "LDAP {ARGON2} " + r.Algorithm
+		return r
+	}
+	return HashReport{Algorithm: "LDAP {" + scheme + "}", Rating: ratingUnknown}
+}
+
+func analyzeSCRAMHash(h string) HashReport {
+	r := HashReport{Algorithm: "SCRAM-SHA-256"}
+	spec := strings.SplitN(strings.TrimPrefix(h, "SCRAM-SHA-256$"), ":", 2)[0]
+	iter, _ := strconv.Atoi(spec)
+	r.Parameters = fmt.Sprintf("i=%d", iter)
+	if iter < minSCRAMIterations {
+		r.Rating = ratingWeak
+		r.Issues = append(r.Issues, fmt.Sprintf("%d iterations is below the default %d", iter, minSCRAMIterations))
+	} else {
+		r.Rating = ratingAcceptable
+	}
+	return r
+}
+
+// parsePHCParams parses a PHC parameter field such as m=65536,t=3,p=4
+func parsePHCParams(s string) map[string]int {
+	params := map[string]int{}
+	for _, kv := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.Atoi(v)
+		params[k] = n
+	}
+	return params
+}
+
+// mysqlRounds converts the caching_sha2_password iteration field (in
+// thousands, hex) to a round count
+func mysqlRounds(parts []string) string {
+	if len(parts) < 3 {
+		return strconv.Itoa(shaCryptRoundsDef)
+	}
+	n, err := strconv.ParseInt(parts[2], 16, 32)
+	if err != nil {
+		return strconv.Itoa(shaCryptRoundsDef)
+	}
+	return strconv.Itoa(int(n) * 1000)
+}
+
+func isHexLen(s string, n int) bool {
+	return len(s) == n && hexHashRe.MatchString(s)
+}
+
+// hashCheckResults converts hash reports into check results; file, when set,
+// locates each result for SARIF
+func hashCheckResults(reports []HashReport, file string) []checkResult {
+	results := make([]checkResult, 0, len(reports))
+	for i, r := range reports {
+		name := r.User
+		if name == "" {
+			name = fmt.Sprintf("hash %d", i+1)
+		}
+		msg := r.Algorithm
+		if r.Parameters != "" {
+			msg += " (" + r.Parameters + ")"
+		}
+		msg += " is rated " + r.Rating
+		if len(r.Issues) > 0 {
+			msg += ": " + strings.Join(r.Issues, "; ")
+		}
+		res := checkResult{Name: name, Rule: "hash-" + r.Rating, Passed: !failedRating(r.Rating), Message: msg}
+		if file != "" {
+			res.Path, res.Line = file, r.Line
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// failedRating reports whether a rating should fail an audit
+func failedRating(rating string) bool {
+	return rating == ratingWeak || rating == ratingBroken
+}
+
+// ratingRules describes the failing ratings as SARIF rules
+func ratingRules(prefix, what string) []sarifRule {
+	return []sarifRule{
+		{ID: prefix + "-" + ratingWeak, ShortDescription: sarifMessage{Text: "Weak " + what}},
+		{ID: prefix + "-" + ratingBroken, ShortDescription: sarifMessage{Text: "Broken " + what}},
+	}
+}
+
+// printHashReports outputs the reports as text
+func printHashReports(reports []HashReport) {
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Println()
+		}
+		if r.User != "" {
+			fmt.Printf("User: %s\n", r.User)
+		}
+		fmt.Printf("Algorithm: %s\n", r.Algorithm)
+		if r.Parameters != "" {
+			fmt.Printf("Parameters: %s\n", r.Parameters)
+		}
+		fmt.Printf("Rating: %s\n", strings.ToUpper(r.Rating[:1])+r.Rating[1:])
+		if len(r.Issues) > 0 {
+			fmt.Printf("Issues:\n  - %s\n", strings.Join(r.Issues, "\n  - "))
+		}
+	}
+}
+
+func init() {
+	analyzeCmd.AddCommand(analyzeHashCmd)
+
+	analyzeHashCmd.Flags().StringP("file", "f", "", "read a shadow, htpasswd or pwdump file")
+	analyzeHashCmd.Flags().Bool("stdin", false, "read hashes from STDIN, one per line")
+	analyzeHashCmd.Flags().Bool("json", false, "output as JSON (same as --format json)")
+	addReportFormatFlag(analyzeHashCmd)
+}
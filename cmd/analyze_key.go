@@ -0,0 +1,526 @@
+// cmd/analyze_key.go
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+// Key size thresholds (NIST SP 800-57: 2048-bit RSA is acceptable until 2030)
+const (
+	minRSABits    = 2048
+	goodRSABits   = 3072
+	brokenRSABits = 1024
+	minRSAExp     = 65537
+	// Debian blacklist entries are the last 20 hex digits of the fingerprint
+	blacklistEntryLen = 20
+)
+
+// KeyReport describes the quality of a single public or private key
+type KeyReport struct {
+	Source      string   `json:"source"`
+	Type        string   `json:"type"`
+	Bits        int      `json:"bits,omitempty"`
+	Curve       string   `json:"curve,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	Comment     string   `json:"comment,omitempty"`
+	Private     bool     `json:"private,omitempty"`
+	DebianWeak  string   `json:"debian_weak_key,omitempty"`
+	Rating      string   `json:"rating"`
+	Issues      []string `json:"issues,omitempty"`
+
+	pub  crypto.PublicKey
+	perm os.FileMode
+}
+
+var analyzeKeyCmd = &cobra.Command{
+	Use:   "key [file...]",
+	Short: "Audit public and private keys for weak parameters",
+	Long: `Audit PEM (PKCS#1, PKCS#8, SEC1, X.509), OpenSSH private keys and
+authorized_keys / .pub files for:
+
+  - algorithm and key size adequacy
+  - small or invalid RSA public exponents
+  - RSA moduli that share a prime factor with another key in the set
+    (batch GCD across every key given)
+  - known Debian weak keys (CVE-2008-0166), using openssh-blacklist or
+    openssl-blacklist files passed with --blacklist
+  - ECDSA curve support and point validity
+  - private key files readable by group or others
+
+The command fails if any key is rated weak or broken.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useStdin, _ := cmd.Flags().GetBool("stdin")
+		blacklists, _ := cmd.Flags().GetStringSlice("blacklist")
+		format, err := getReportFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 && !useStdin {
+			return fmt.Errorf("provide one or more key files or use --stdin")
+		}
+
+		var reports []*KeyReport
+		for _, name := range args {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return fmt.Errorf("failed to read key file: %w", err)
+			}
+			rs := parseKeys(name, data)
+			if info, err := os.Stat(name); err == nil {
+				for _, r := range rs {
+					r.perm = info.Mode().Perm()
+				}
+			}
+			reports = append(reports, rs...)
+		}
+		if useStdin {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read keys: %w", err)
+			}
+			reports = append(reports, parseKeys("stdin", data)...)
+		}
+		if len(reports) == 0 {
+			return fmt.Errorf("no keys found")
+		}
+
+		blacklist, err := loadKeyBlacklists(blacklists)
+		if err != nil {
+			return err
+		}
+		for _, r := range reports {
+			checkKey(r, blacklist)
+		}
+		checkSharedFactors(reports)
+
+		switch format {
+		case "json":
+			err = printJSON(os.Stdout, reports, "key report")
+		case "sarif":
+			err = printSARIF(keyCheckResults(reports), ratingRules("key", "key material"))
+		case "junit":
+			err = printJUnit("keyforge analyze key", keyCheckResults(reports))
+		default:
+			printKeyReports(reports)
+		}
+		if err != nil {
+			return err
+		}
+
+		bad := 0
+		for _, r := range reports {
+			if failedRating(r.Rating) {
+				bad++
+			}
+		}
+		if bad > 0 {
+			return fmt.Errorf("%d of %d keys are weak or broken", bad, len(reports))
+		}
+		return nil
+	},
+}
+
+// parseKeys extracts every key from PEM blocks or authorized_keys lines
+func parseKeys(name string, data []byte) []*KeyReport {
+	if bytes.Contains(data, []byte("-----BEGIN ")) {
+		return parsePEMKeys(name, data)
+	}
+
+	var reports []*KeyReport
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		source := fmt.Sprintf("%s:%d", name, n)
+		pub, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			reports = append(reports, &KeyReport{Source: source, Type: "unrecognized", Rating: ratingUnknown, Issues: []string{"not a valid public key line"}})
+			continue
+		}
+		r := sshKeyReport(source, pub)
+		r.Comment = comment
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// parsePEMKeys extracts the key from each PEM block
+func parsePEMKeys(name string, data []byte) []*KeyReport {
+	var reports []*KeyReport
+	for n := 1; ; n++ {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		source := fmt.Sprintf("%s#%d", name, n)
+		r := &KeyReport{Source: source, Type: "unrecognized", Rating: ratingUnknown}
+
+		var err error
+		switch {
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			r.Private = true
+			var key any
+			key, err = ssh.ParseRawPrivateKey(pem.EncodeToMemory(block))
+			var missing *ssh.PassphraseMissingError
+			switch {
+			case errors.As(err, &missing) && missing.PublicKey != nil:
+				pr := sshKeyReport(source, missing.PublicKey)
+				pr.Private = true
+				r, err = pr, nil
+			case errors.As(err, &missing):
+				r.Issues = append(r.Issues, "encrypted private key; public key is not available without the passphrase")
+				err = nil
+			case err == nil:
+				if signer, ok := key.(crypto.Signer); ok {
+					r.pub = signer.Public()
+				} else if dk, ok := key.(*dsa.PrivateKey); ok {
+					r.pub = &dk.PublicKey
+				}
+			}
+		case block.Type == "PUBLIC KEY":
+			r.pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case block.Type == "RSA PUBLIC KEY":
+			r.pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case block.Type == "CERTIFICATE":
+			var cert *x509.Certificate
+			if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+				r.pub, r.Comment = cert.PublicKey, cert.Subject.CommonName
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			r.Issues = append(r.Issues, "failed to parse "+strings.ToLower(block.Type)+": "+err.Error())
+		}
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// sshKeyReport starts a report for an SSH wire-format public key
+func sshKeyReport(source string, pub ssh.PublicKey) *KeyReport {
+	if cert, ok := pub.(*ssh.Certificate); ok {
+		pub = cert.Key
+	}
+	r := &KeyReport{Source: source, Type: pub.Type(), Rating: ratingUnknown, Fingerprint: ssh.FingerprintSHA256(pub)}
+	if cpk, ok := pub.(ssh.CryptoPublicKey); ok {
+		r.pub = cpk.CryptoPublicKey()
+	} else if strings.HasPrefix(pub.Type(), "sk-") {
+		// FIDO/U2F security key; the private half never leaves the token
+		r.Rating = ratingStrong
+	}
+	return r
+}
+
+// checkKey rates algorithm, size, exponent, curve, blacklist membership and
+// private key file permissions
+func checkKey(r *KeyReport, blacklist map[string]bool) {
+	checkKeyMaterial(r, blacklist)
+	if r.Private && r.perm&0o077 != 0 {
+		downgradeKey(r, ratingWeak, fmt.Sprintf("private key file is readable by group or others (mode %04o)", r.perm))
+	}
+}
+
+func checkKeyMaterial(r *KeyReport, blacklist map[string]bool) {
+	if r.pub == nil {
+		return
+	}
+	if r.Fingerprint == "" {
+		if sp, err := ssh.NewPublicKey(r.pub); err == nil {
+			r.Fingerprint = ssh.FingerprintSHA256(sp)
+		}
+	}
+	r.Rating = ratingStrong
+
+	switch k := r.pub.(type) {
+	case *rsa.PublicKey:
+		r.Type, r.Bits = "rsa", k.N.BitLen()
+		switch {
+		case r.Bits < brokenRSABits:
+			downgradeKey(r, ratingBroken, fmt.Sprintf("%d-bit RSA can be factored", r.Bits))
+		case r.Bits < minRSABits:
+			downgradeKey(r, ratingWeak, fmt.Sprintf("%d-bit RSA is below the %d-bit minimum", r.Bits, minRSABits))
+		case r.Bits < goodRSABits:
+			downgradeKey(r, ratingAcceptable, fmt.Sprintf("consider %d-bit RSA or ed25519 for keys used beyond 2030", goodRSABits))
+		}
+		switch {
+		case k.E <= 1 || k.E%2 == 0:
+			downgradeKey(r, ratingBroken, fmt.Sprintf("invalid public exponent %d", k.E))
+		case k.E < minRSAExp:
+			downgradeKey(r, ratingWeak, fmt.Sprintf("small public exponent %d (use %d)", k.E, minRSAExp))
+		}
+		if k.N.Bit(0) == 0 {
+			downgradeKey(r, ratingBroken, "modulus is even")
+		}
+		checkDebianWeakKey(r, blacklist)
+	case *dsa.PublicKey:
+		r.Type, r.Bits = "dsa", k.P.BitLen()
+		downgradeKey(r, ratingWeak, "DSA (ssh-dss) is disabled by default since OpenSSH 7.0")
+		checkDebianWeakKey(r, blacklist)
+	case *ecdsa.PublicKey:
+		r.Type, r.Bits, r.Curve = "ecdsa", k.Curve.Params().BitSize, k.Curve.Params().Name
+		if _, err := k.ECDH(); err != nil {
+			if r.Bits < 256 {
+				downgradeKey(r, ratingWeak, fmt.Sprintf("curve %s is below 128-bit security", r.Curve))
+			} else {
+				downgradeKey(r, ratingBroken, "point is not on the curve or the curve is unsupported")
+			}
+		}
+	case ed25519.PublicKey:
+		r.Type, r.Bits = "ed25519", 256
+		if len(k) != ed25519.PublicKeySize {
+			downgradeKey(r, ratingBroken, "malformed ed25519 key")
+		}
+	default:
+		r.Rating = ratingUnknown
+		r.Issues = append(r.Issues, fmt.Sprintf("unsupported key type %T", k))
+	}
+}
+
+// checkSharedFactors runs a batch GCD over all RSA moduli and marks keys
+// whose modulus shares a prime with another key in the set
+func checkSharedFactors(reports []*KeyReport) {
+	var moduli []*big.Int
+	owners := map[string][]*KeyReport{}
+	for _, r := range reports {
+		if k, ok := r.pub.(*rsa.PublicKey); ok {
+			key := k.N.String()
+			if _, seen := owners[key]; !seen {
+				moduli = append(moduli, k.N)
+			}
+			owners[key] = append(owners[key], r)
+		}
+	}
+	if len(moduli) < 2 {
+		return
+	}
+
+	gcds := batchGCD(moduli)
+	one := big.NewInt(1)
+	var weak []int
+	for i, g := range gcds {
+		if g.Cmp(one) != 0 {
+			weak = append(weak, i)
+		}
+	}
+
+	// Name the partners; the flagged set is small, so pairwise is fine here
+	for _, i := range weak {
+		var partners []string
+		for _, j := range weak {
+			if i != j && new(big.Int).GCD(nil, nil, moduli[i], moduli[j]).Cmp(one) != 0 {
+				partners = append(partners, owners[moduli[j].String()][0].Source)
+			}
+		}
+		for _, r := range owners[moduli[i].String()] {
+			downgradeKey(r, ratingBroken, "modulus shares a prime factor with "+strings.Join(partners, ", ")+"; the private key is recoverable")
+		}
+	}
+}
+
+// batchGCD returns gcd(n_i, prod_{j != i} n_j) for each modulus using
+// Bernstein's product and remainder trees
+func batchGCD(moduli []*big.Int) []*big.Int {
+	tree := [][]*big.Int{moduli}
+	for level := moduli; len(level) > 1; {
+		next := make([]*big.Int, (len(level)+1)/2)
+		for i := range next {
+			if 2*i+1 < len(level) {
+				next[i] = new(big.Int).Mul(level[2*i], level[2*i+1])
+			} else {
+				next[i] = level[2*i]
+			}
+		}
+		tree = append(tree, next)
+		level = next
+	}
+
+	rems := tree[len(tree)-1]
+	for l := len(tree) - 2; l >= 0; l-- {
+		level := tree[l]
+		next := make([]*big.Int, len(level))
+		for i, n := range level {
+			sq := new(big.Int).Mul(n, n)
+			next[i] = new(big.Int).Mod(rems[i/2], sq)
+		}
+		rems = next
+	}
+
+	out := make([]*big.Int, len(moduli))
+	for i, n := range moduli {
+		q := new(big.Int).Quo(rems[i], n)
+		out[i] = new(big.Int).GCD(nil, nil, n, q)
+	}
+	return out
+}
+
+// loadKeyBlacklists reads openssh-blacklist / openssl-blacklist files
+func loadKeyBlacklists(paths []string) (map[string]bool, error) {
+	set := map[string]bool{}
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open blacklist: %w", err)
+		}
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			line := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s.Text()), ":", ""))
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if len(line) > blacklistEntryLen {
+				line = line[len(line)-blacklistEntryLen:]
+			}
+			set[line] = true
+		}
+		err = s.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blacklist: %w", err)
+		}
+	}
+	return set, nil
+}
+
+// checkDebianWeakKey records whether an RSA or DSA key is on the Debian
+// weak-key blacklist, or that it was not checked because none was given
+func checkDebianWeakKey(r *KeyReport, blacklist map[string]bool) {
+	switch {
+	case len(blacklist) == 0:
+		r.DebianWeak = "not checked"
+	case blacklisted(r, blacklist):
+		r.DebianWeak = "listed"
+		downgradeKey(r, ratingBroken, "known Debian weak key (CVE-2008-0166)")
+	default:
+		r.DebianWeak = "not listed"
+	}
+}
+
+// blacklisted checks the key's MD5 SSH fingerprint (openssh-blacklist) and
+// SHA-1 of its modulus line (openssl-blacklist) against the set
+func blacklisted(r *KeyReport, set map[string]bool) bool {
+	if len(set) == 0 {
+		return false
+	}
+	var candidates []string
+	if sp, err := ssh.NewPublicKey(r.pub); err == nil {
+		candidates = append(candidates, strings.ReplaceAll(ssh.FingerprintLegacyMD5(sp), ":", ""))
+	}
+	if k, ok := r.pub.(*rsa.PublicKey); ok {
+		sum := sha1.Sum([]byte(fmt.Sprintf("Modulus=%X\n", k.N)))
+		candidates = append(candidates, hex.EncodeToString(sum[:]))
+	}
+	for _, c := range candidates {
+		if set[c[len(c)-blacklistEntryLen:]] {
+			return true
+		}
+	}
+	return false
+}
+
+// downgradeKey lowers the rating (never raises it) and records why
+func downgradeKey(r *KeyReport, rating, issue string) {
+	order := map[string]int{ratingStrong: 0, ratingAcceptable: 1, ratingUnknown: 1, ratingWeak: 2, ratingBroken: 3}
+	if order[rating] > order[r.Rating] {
+		r.Rating = rating
+	}
+	r.Issues = append(r.Issues, issue)
+}
+
+// keyCheckResults converts key reports into check results, locating
+// authorized_keys entries (file:line) and PEM blocks (file#n) for SARIF
+func keyCheckResults(reports []*KeyReport) []checkResult {
+	results := make([]checkResult, 0, len(reports))
+	for _, r := range reports {
+		msg := r.Type
+		if r.Bits > 0 {
+			msg += fmt.Sprintf(" %d-bit", r.Bits)
+		}
+		msg += " key is rated " + r.Rating
+		if len(r.Issues) > 0 {
+			msg += ": " + strings.Join(r.Issues, "; ")
+		}
+		res := checkResult{Name: r.Source, Rule: "key-" + r.Rating, Passed: !failedRating(r.Rating), Message: msg, Path: r.Source}
+		if i := strings.LastIndexAny(r.Source, ":#"); i > 0 {
+			res.Path = r.Source[:i]
+			if r.Source[i] == ':' {
+				fmt.Sscan(r.Source[i+1:], &res.Line)
+			}
+		}
+		if res.Path == "stdin" {
+			res.Path = ""
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// printKeyReports outputs the reports as text
+func printKeyReports(reports []*KeyReport) {
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("Source: %s\n", r.Source)
+		kind := r.Type
+		if r.Private {
+			kind += " (private)"
+		}
+		fmt.Printf("Type: %s\n", kind)
+		if r.Bits > 0 {
+			fmt.Printf("Bits: %d\n", r.Bits)
+		}
+		if r.Curve != "" {
+			fmt.Printf("Curve: %s\n", r.Curve)
+		}
+		if r.Fingerprint != "" {
+			fmt.Printf("Fingerprint: %s\n", r.Fingerprint)
+		}
+		if r.Comment != "" {
+			fmt.Printf("Comment: %s\n", r.Comment)
+		}
+		if r.DebianWeak == "not checked" {
+			fmt.Println("Debian weak key: not checked (pass --blacklist)")
+		} else if r.DebianWeak != "" {
+			fmt.Printf("Debian weak key: %s\n", r.DebianWeak)
+		}
+		fmt.Printf("Rating: %s\n", strings.ToUpper(r.Rating[:1])+r.Rating[1:])
+		if len(r.Issues) > 0 {
+			fmt.Printf("Issues:\n  - %s\n", strings.Join(r.Issues, "\n  - "))
+		}
+	}
+}
+
+func init() {
+	analyzeCmd.AddCommand(analyzeKeyCmd)
+
+	analyzeKeyCmd.Flags().Bool("stdin", false, "read keys from STDIN")
+	analyzeKeyCmd.Flags().StringSlice("blacklist", nil, "Debian openssh-blacklist or openssl-blacklist file (repeatable)")
+	analyzeKeyCmd.Flags().Bool("json", false, "output as JSON (same as --format json)")
+	addReportFormatFlag(analyzeKeyCmd)
+}
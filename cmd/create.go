@@ -6,6 +6,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"math/big"
 	"os"
 	"strings"
@@ -15,15 +17,33 @@ import (
 
 // Config holds password generation parameters
 type Config struct {
-	Length int
-	Count  int
-	AsJSON bool
+	Length     int
+	Count      int
+	AsJSON     bool
+	Unique     bool
+	Against    string
+	Ledger     string
+	Hash       string
+	Credential string
+	User       string
+	Output     OutputConfig
 }
 
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create keys and passwords",
-	Long:  "Generate keys/passwords in various styles (easy, strong, WEP keys).",
+	Long: `Generate keys/passwords in various styles (easy, strong, WEP keys).
+
+Every create command accepts --output json|ndjson|yaml|csv|tsv|env|table to
+emit its values with metadata (type, length, entropy bits, created time), or
+--template to render each value with a Go template such as '{{.Value}}'.
+
+With --encrypt-to age1... or --recipient-file (age recipients or an OpenPGP
+public key) everything printed and every private file written is encrypted,
+so plaintext never reaches the terminal or disk.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateOutputFlags(cmd)
+	},
 }
 
 var createEasyCmd = &cobra.Command{
@@ -32,16 +52,16 @@ var createEasyCmd = &cobra.Command{
 	Long:  "Generate memorable passwords using alternating consonants, vowels, and digits",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := getConfigFromFlags(cmd)
-		results := make([]string, 0, cfg.Count)
-		
-		for i := 0; i < cfg.Count; i++ {
-			password, err := genEasyWithError(cfg.Length)
-			if err != nil {
-				return fmt.Errorf("failed to generate easy password: %w", err)
-			}
-			results = append(results, password)
+		if err := validatePasswordOutput(cfg); err != nil {
+			return err
+		}
+		results, err := generateBatch(cmd, cfg, easyEntropyBits(cfg.Length), func() (string, error) {
+			return genEasyWithError(cfg.Length)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate easy password: %w", err)
 		}
-		return printResults(results, cfg.AsJSON)
+		return printPasswordResults(cmd.OutOrStdout(), results, "easy", easyEntropyBits(cfg.Length), cfg)
 	},
 }
 
@@ -51,16 +71,16 @@ var createStrongCmd = &cobra.Command{
 	Long:  "Generate cryptographically strong passwords using mixed character sets",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := getConfigFromFlags(cmd)
-		results := make([]string, 0, cfg.Count)
-		
-		for i := 0; i < cfg.Count; i++ {
-			password, err := genStrongWithError(cfg.Length)
-			if err != nil {
-				return fmt.Errorf("failed to generate strong password: %w", err)
-			}
-			results = append(results, password)
+		if err := validatePasswordOutput(cfg); err != nil {
+			return err
+		}
+		results, err := generateBatch(cmd, cfg, strongEntropyBits(cfg.Length), func() (string, error) {
+			return genStrongWithError(cfg.Length)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate strong password: %w", err)
 		}
-		return printResults(results, cfg.AsJSON)
+		return printPasswordResults(cmd.OutOrStdout(), results, "strong", strongEntropyBits(cfg.Length), cfg)
 	},
 }
 
@@ -70,16 +90,13 @@ var createWEP64Cmd = &cobra.Command{
 	Long:  "Generate a 64-bit WEP key with 40 bits of actual key material (5 bytes = 10 hex characters)",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := getConfigFromFlags(cmd)
-		results := make([]string, 0, cfg.Count)
-		
-		for i := 0; i < cfg.Count; i++ {
-			key, err := genWEPHexBytesWithError(5) // 5 bytes -> 10 hex chars
-			if err != nil {
-				return fmt.Errorf("failed to generate 64-bit WEP key: %w", err)
-			}
-			results = append(results, key)
+		results, err := generateBatch(cmd, cfg, 5*8, func() (string, error) {
+			return genWEPHexBytesWithError(5) // 5 bytes -> 10 hex chars
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate 64-bit WEP key: %w", err)
 		}
-		return printResults(results, cfg.AsJSON)
+		return printSecretResults(cmd.OutOrStdout(), results, "wep64", 5*8, cfg)
 	},
 }
 
@@ -89,16 +106,13 @@ var createWEP128Cmd = &cobra.Command{
 	Long:  "Generate a 128-bit WEP key with 104 bits of actual key material (13 bytes = 26 hex characters)",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := getConfigFromFlags(cmd)
-		results := make([]string, 0, cfg.Count)
-		
-		for i := 0; i < cfg.Count; i++ {
-			key, err := genWEPHexBytesWithError(13) // 13 bytes -> 26 hex chars
-			if err != nil {
-				return fmt.Errorf("failed to generate 128-bit WEP key: %w", err)
-			}
-			results = append(results, key)
+		results, err := generateBatch(cmd, cfg, 13*8, func() (string, error) {
+			return genWEPHexBytesWithError(13) // 13 bytes -> 26 hex chars
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate 128-bit WEP key: %w", err)
 		}
-		return printResults(results, cfg.AsJSON)
+		return printSecretResults(cmd.OutOrStdout(), results, "wep128", 13*8, cfg)
 	},
 }
 
@@ -108,16 +122,13 @@ var createWEP256Cmd = &cobra.Command{
 	Long:  "Generate a 256-bit WEP key with 232 bits of actual key material (29 bytes = 58 hex characters)",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := getConfigFromFlags(cmd)
-		results := make([]string, 0, cfg.Count)
-		
-		for i := 0; i < cfg.Count; i++ {
-			key, err := genWEPHexBytesWithError(29) // 29 bytes -> 58 hex chars
-			if err != nil {
-				return fmt.Errorf("failed to generate 256-bit WEP key: %w", err)
-			}
-			results = append(results, key)
+		results, err := generateBatch(cmd, cfg, 29*8, func() (string, error) {
+			return genWEPHexBytesWithError(29) // 29 bytes -> 58 hex chars
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate 256-bit WEP key: %w", err)
 		}
-		return printResults(results, cfg.AsJSON)
+		return printSecretResults(cmd.OutOrStdout(), results, "wep256", 29*8, cfg)
 	},
 }
 
@@ -126,14 +137,37 @@ func getConfigFromFlags(cmd *cobra.Command) Config {
 	length, _ := cmd.Flags().GetInt("length")
 	count, _ := cmd.Flags().GetInt("count")
 	asJSON, _ := cmd.Flags().GetBool("json")
+	unique, _ := cmd.Flags().GetBool("unique")
+	against, _ := cmd.Flags().GetString("against")
+	ledger, _ := cmd.Flags().GetString("ledger")
+	hash, _ := cmd.Flags().GetString("hash")
+	credential, _ := cmd.Flags().GetString("credential")
+	user, _ := cmd.Flags().GetString("user")
 	
 	return Config{
-		Length: length,
-		Count:  count,
-		AsJSON: asJSON,
+		Length:     length,
+		Count:      count,
+		AsJSON:     asJSON,
+		Unique:     unique || against != "" || ledger != "",
+		Against:    against,
+		Ledger:     ledger,
+		Hash:       strings.ToLower(hash),
+		Credential: strings.ToLower(credential),
+		User:       user,
+		Output:     getOutputConfigFromFlags(cmd),
 	}
 }
 
+// addUniqueFlags registers the batch uniqueness flags on a create subcommand.
+// Call it after addOutputFlags so the ledger is only updated once the output
+// has been written.
+func addUniqueFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("unique", false, "guarantee no duplicates within the batch")
+	cmd.Flags().String("against", "", "also reject values listed in this file (one per line; implies --unique)")
+	cmd.Flags().String("ledger", "", "bbolt ledger of keyed hashes of issued values to check and update; keep it private (implies --unique)")
+	cmd.RunE = withLedger(cmd.RunE)
+}
+
 // genWEPHexBytes generates n random bytes and returns them as a hex string
 func genWEPHexBytes(n int) string {
 	result, err := genWEPHexBytesWithError(n)
@@ -147,15 +181,24 @@ func genWEPHexBytes(n int) string {
 
 // genWEPHexBytesWithError is the internal version that returns errors
 func genWEPHexBytesWithError(n int) (string, error) {
+	b, err := genRandomBytes(n)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// genRandomBytes returns n bytes from crypto/rand
+func genRandomBytes(n int) ([]byte, error) {
 	if n <= 0 {
-		return "", fmt.Errorf("byte count must be positive, got: %d", n)
+		return nil, fmt.Errorf("byte count must be positive, got: %d", n)
 	}
 	
 	b := make([]byte, n)
 	if _, err := rand.Read(b); err != nil {
-		return "", fmt.Errorf("failed to read random bytes: %w", err)
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
 	}
-	return hex.EncodeToString(b), nil
+	return b, nil
 }
 
 func init() {
@@ -187,10 +230,48 @@ func init() {
 
 	createWEP256Cmd.Flags().IntP("count", "c", 1, "number of keys to generate")
 	createWEP256Cmd.Flags().Bool("json", false, "output as JSON array")
+
+	for _, c := range []*cobra.Command{createEasyCmd, createStrongCmd, createWEP64Cmd, createWEP128Cmd, createWEP256Cmd} {
+		addOutputFlags(c)
+		addUniqueFlags(c)
+	}
+	for _, c := range []*cobra.Command{createEasyCmd, createStrongCmd} {
+		c.Flags().String("hash", "", "also output a storage hash for each password ("+strings.Join(hashAlgorithms, "|")+")")
+		c.Flags().String("credential", "", "also output each password in a system's credential format ("+strings.Join(credentialFormats, "|")+")")
+		c.Flags().String("user", "", "account name for credential formats that include one (htpasswd)")
+	}
 }
 
 // ---- Password Generators ----
 
+// Character pools for pronounceable (easy) and strong passwords
+const (
+	easyVowels     = "aeiou"
+	easyConsonants = "bcdfghjklmnpqrstvwxyz"
+	easyDigits     = "0123456789"
+	strongPool     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()-_=+[]{};:,.<>/?~"
+)
+
+// Password length limits; create easy and strong raise shorter lengths to
+// the minimum, generator specs reject lengths outside them
+const (
+	minEasyLength     = 4
+	minStrongLength   = 8
+	maxPasswordLength = 1024
+)
+
+// easyPool returns the character pool used at position i of an easy password
+func easyPool(i int) string {
+	switch {
+	case i%3 == 2: // Every third character is a digit
+		return easyDigits
+	case i%2 == 0: // Even positions get consonants
+		return easyConsonants
+	default: // Odd positions get vowels
+		return easyVowels
+	}
+}
+
 // genEasy generates a memorable password using alternating consonants, vowels, and digits
 func genEasy(n int) string {
 	result, err := genEasyWithError(n)
@@ -204,30 +285,15 @@ func genEasy(n int) string {
 
 // genEasyWithError is the internal version that returns errors
 func genEasyWithError(n int) (string, error) {
-	if n < 4 {
-		n = 4
+	if n < minEasyLength {
+		n = minEasyLength
 	}
 	
-	// Character pools for pronounceable passwords
-	const vowels = "aeiou"
-	const consonants = "bcdfghjklmnpqrstvwxyz"
-	const digits = "0123456789"
-
 	var b strings.Builder
 	b.Grow(n) // Pre-allocate capacity
 	
 	for i := 0; i < n; i++ {
-		var pool string
-		switch {
-		case i%3 == 2: // Every third character is a digit
-			pool = digits
-		case i%2 == 0: // Even positions get consonants
-			pool = consonants
-		default: // Odd positions get vowels
-			pool = vowels
-		}
-		
-		ch, err := randChoice(pool)
+		ch, err := randChoice(easyPool(i))
 		if err != nil {
 			return "", fmt.Errorf("failed to select random character: %w", err)
 		}
@@ -236,6 +302,18 @@ func genEasyWithError(n int) (string, error) {
 	return b.String(), nil
 }
 
+// easyEntropyBits returns the keyspace size in bits of an easy password of length n
+func easyEntropyBits(n int) float64 {
+	if n < minEasyLength {
+		n = minEasyLength
+	}
+	bits := 0.0
+	for i := 0; i < n; i++ {
+		bits += math.Log2(float64(len(easyPool(i))))
+	}
+	return bits
+}
+
 // genStrong generates a cryptographically strong password using mixed character sets
 func genStrong(n int) string {
 	result, err := genStrongWithError(n)
@@ -249,17 +327,15 @@ func genStrong(n int) string {
 
 // genStrongWithError is the internal version that returns errors
 func genStrongWithError(n int) (string, error) {
-	if n < 8 {
-		n = 8
+	if n < minStrongLength {
+		n = minStrongLength
 	}
 	
-	const pool = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()-_=+[]{};:,.<>/?~"
-	
 	var b strings.Builder
 	b.Grow(n) // Pre-allocate capacity
 	
 	for i := 0; i < n; i++ {
-		ch, err := randChoice(pool)
+		ch, err := randChoice(strongPool)
 		if err != nil {
 			return "", fmt.Errorf("failed to select random character: %w", err)
 		}
@@ -268,28 +344,24 @@ func genStrongWithError(n int) (string, error) {
 	return b.String(), nil
 }
 
+// strongEntropyBits returns the keyspace size in bits of a strong password of length n
+func strongEntropyBits(n int) float64 {
+	if n < minStrongLength {
+		n = minStrongLength
+	}
+	return float64(n) * math.Log2(float64(len(strongPool)))
+}
+
 // Fallback functions for when crypto/rand fails (extremely unlikely)
 func genEasyFallback(n int) string {
-	if n < 4 {
-		n = 4
+	if n < minEasyLength {
+		n = minEasyLength
 	}
-	const vowels = "aeiou"
-	const consonants = "bcdfghjklmnpqrstvwxyz"
-	const digits = "0123456789"
-
 	var b strings.Builder
 	b.Grow(n)
 	
 	for i := 0; i < n; i++ {
-		var pool string
-		switch {
-		case i%3 == 2:
-			pool = digits
-		case i%2 == 0:
-			pool = consonants
-		default:
-			pool = vowels
-		}
+		pool := easyPool(i)
 		// Use a simple modulo fallback (not cryptographically secure)
 		b.WriteByte(pool[i%len(pool)])
 	}
@@ -297,17 +369,15 @@ func genEasyFallback(n int) string {
 }
 
 func genStrongFallback(n int) string {
-	if n < 8 {
-		n = 8
+	if n < minStrongLength {
+		n = minStrongLength
 	}
-	const pool = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()-_=+[]{};:,.<>/?~"
-	
 	var b strings.Builder
 	b.Grow(n)
 	
 	for i := 0; i < n; i++ {
 		// Use a simple modulo fallback (not cryptographically secure)
-		b.WriteByte(pool[i%len(pool)])
+		b.WriteByte(strongPool[i%len(strongPool)])
 	}
 	return b.String()
 }
@@ -326,8 +396,59 @@ func randChoice(pool string) (byte, error) {
 	return pool[ri.Int64()], nil
 }
 
+// validatePasswordOutput checks --hash and --credential before anything is
+// generated, so a typo does not consume ledger entries
+func validatePasswordOutput(cfg Config) error {
+	if cfg.Hash != "" {
+		if err := validateHashAlgorithm(cfg.Hash); err != nil {
+			return err
+		}
+	}
+	if cfg.Credential != "" {
+		if err := validateCredentialFormat(cfg.Credential); err != nil {
+			return err
+		}
+		if cfg.Credential == "htpasswd" && cfg.User == "" {
+			return fmt.Errorf("--credential htpasswd requires --user")
+		}
+	}
+	return nil
+}
+
+// printPasswordResults prints generated passwords, paired with their hashes
+// and credentials when --hash or --credential is set
+func printPasswordResults(w io.Writer, results []string, typ string, bits float64, cfg Config) error {
+	if cfg.Hash == "" && cfg.Credential == "" {
+		return printSecretResults(w, results, typ, bits, cfg)
+	}
+	hashed, err := hashResults(results, cfg)
+	if err != nil {
+		return err
+	}
+	if !cfg.Output.structured() {
+		return printHashedResults(w, hashed, cfg.AsJSON)
+	}
+
+	items := make([]SecretItem, 0, len(hashed))
+	for _, h := range hashed {
+		it := newSecretItem(typ, h.Password, bits)
+		it.User, it.Hash, it.Credential = h.User, h.Hash, h.Credential
+		items = append(items, it)
+	}
+	return printSecretItems(w, items, cfg.Output)
+}
+
+// printSecretResults prints a batch of generated values with --output, or
+// as plain lines or a JSON array
+func printSecretResults(w io.Writer, results []string, typ string, bits float64, cfg Config) error {
+	if cfg.Output.structured() {
+		return printSecretItems(w, newSecretItems(typ, results, bits), cfg.Output)
+	}
+	return printResults(w, results, cfg.AsJSON)
+}
+
 // printResults outputs the results either as JSON or plain text
-func printResults(results []string, jsonOut bool) error {
+func printResults(w io.Writer, results []string, jsonOut bool) error {
 	if len(results) == 0 {
 		return fmt.Errorf("no results to print")
 	}
@@ -337,13 +458,13 @@ func printResults(results []string, jsonOut bool) error {
 		if err != nil {
 			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		fmt.Println(string(data))
+		fmt.Fprintln(w, string(data))
 		return nil
 	}
 
 	// Plain text output
 	for _, result := range results {
-		fmt.Println(result)
+		fmt.Fprintln(w, result)
 	}
 	return nil
 }
@@ -0,0 +1,492 @@
+// cmd/create_cert.go
+package cmd
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// CertConfig holds configuration for certificate, CA and CSR generation
+type CertConfig struct {
+	CommonName   string
+	Organization string
+	SANs         []string
+	Days         int
+	KeyType      string
+	Bits         int
+	CACert       string
+	CAKey        string
+	Out          string
+	AsJSON       bool
+	Output       OutputConfig
+}
+
+// CertBundle is generated X.509 material plus its metadata
+type CertBundle struct {
+	Kind        string    `json:"kind"`
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer,omitempty"`
+	Serial      string    `json:"serial,omitempty"`
+	NotBefore   time.Time `json:"not_before,omitzero"`
+	NotAfter    time.Time `json:"not_after,omitzero"`
+	SANs        []string  `json:"sans,omitempty"`
+	KeyType     string    `json:"key_type"`
+	Bits        int       `json:"bits"`
+	Fingerprint string    `json:"sha256_fingerprint,omitempty"`
+	Certificate string    `json:"certificate,omitempty"`
+	CSR         string    `json:"csr,omitempty"`
+	PrivateKey  string    `json:"private_key,omitempty"`
+	Files       []string  `json:"files,omitempty"`
+}
+
+var createCertCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Create a private key and TLS certificate (self-signed or CA-signed)",
+	Long: `Generate a private key and a leaf certificate for TLS servers and clients.
+
+Without --ca-cert/--ca-key the certificate is self-signed; with them it is
+signed by that CA (see 'keyforge create ca'). Subject alternative names are
+given with --san and may be DNS names, IP addresses, email addresses or URIs.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getCertConfigFromFlags(cmd)
+		if (cfg.CACert == "") != (cfg.CAKey == "") {
+			return fmt.Errorf("--ca-cert and --ca-key must be given together")
+		}
+		bundle, err := genCertificate(cfg, false)
+		if err != nil {
+			return fmt.Errorf("failed to generate certificate: %w", err)
+		}
+		return outputCertBundle(cmd.OutOrStdout(), bundle, cfg)
+	},
+}
+
+var createCACmd = &cobra.Command{
+	Use:   "ca",
+	Short: "Create a local certificate authority",
+	Long: `Generate a self-signed CA certificate and key for signing development leaf
+certificates with 'keyforge create cert --ca-cert ... --ca-key ...'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getCertConfigFromFlags(cmd)
+		bundle, err := genCertificate(cfg, true)
+		if err != nil {
+			return fmt.Errorf("failed to generate CA: %w", err)
+		}
+		return outputCertBundle(cmd.OutOrStdout(), bundle, cfg)
+	},
+}
+
+var createCSRCmd = &cobra.Command{
+	Use:   "csr",
+	Short: "Create a private key and certificate signing request",
+	Long:  "Generate a private key and a PKCS#10 certificate signing request for submission to a CA.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getCertConfigFromFlags(cmd)
+		bundle, err := genCSR(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to generate CSR: %w", err)
+		}
+		return outputCertBundle(cmd.OutOrStdout(), bundle, cfg)
+	},
+}
+
+// getCertConfigFromFlags extracts configuration from command flags
+func getCertConfigFromFlags(cmd *cobra.Command) CertConfig {
+	cn, _ := cmd.Flags().GetString("cn")
+	org, _ := cmd.Flags().GetString("org")
+	sans, _ := cmd.Flags().GetStringSlice("san")
+	days, _ := cmd.Flags().GetInt("days")
+	keyType, _ := cmd.Flags().GetString("key-type")
+	bits, _ := cmd.Flags().GetInt("bits")
+	caCert, _ := cmd.Flags().GetString("ca-cert")
+	caKey, _ := cmd.Flags().GetString("ca-key")
+	out, _ := cmd.Flags().GetString("out")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	return CertConfig{
+		CommonName:   cn,
+		Organization: org,
+		SANs:         sans,
+		Days:         days,
+		KeyType:      strings.ToLower(keyType),
+		Bits:         bits,
+		CACert:       caCert,
+		CAKey:        caKey,
+		Out:          out,
+		AsJSON:       asJSON,
+		Output:       getOutputConfigFromFlags(cmd),
+	}
+}
+
+// certSubject builds the subject name from the configuration
+func certSubject(cfg CertConfig) pkix.Name {
+	name := pkix.Name{CommonName: cfg.CommonName}
+	if cfg.Organization != "" {
+		name.Organization = []string{cfg.Organization}
+	}
+	return name
+}
+
+// applySANs sorts each SAN into the DNS, IP, email or URI field
+func applySANs(sans []string, dns *[]string, ips *[]net.IP, emails *[]string, uris *[]*url.URL) error {
+	for _, san := range sans {
+		san = strings.TrimSpace(san)
+		switch {
+		case san == "":
+			continue
+		case net.ParseIP(san) != nil:
+			*ips = append(*ips, net.ParseIP(san))
+		case strings.Contains(san, "://"):
+			u, err := url.Parse(san)
+			if err != nil {
+				return fmt.Errorf("invalid URI SAN %q: %w", san, err)
+			}
+			*uris = append(*uris, u)
+		case strings.Contains(san, "@"):
+			if _, err := mail.ParseAddress(san); err != nil {
+				return fmt.Errorf("invalid email SAN %q: %w", san, err)
+			}
+			*emails = append(*emails, san)
+		default:
+			*dns = append(*dns, san)
+		}
+	}
+	return nil
+}
+
+// randomSerial returns a random positive 128-bit certificate serial number
+func randomSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// genCertificate creates a key and a self-signed, CA-signed or CA certificate
+func genCertificate(cfg CertConfig, isCA bool) (*CertBundle, error) {
+	if cfg.CommonName == "" {
+		return nil, fmt.Errorf("--cn is required")
+	}
+	if cfg.Days <= 0 {
+		return nil, fmt.Errorf("days must be positive, got: %d", cfg.Days)
+	}
+
+	priv, bits, err := genPrivateKey(cfg.KeyType, cfg.Bits)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	now := time.Now().UTC()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               certSubject(cfg),
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.AddDate(0, 0, cfg.Days),
+		BasicConstraintsValid: true,
+	}
+	if isCA {
+		tmpl.IsCA = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	} else {
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+		sans := cfg.SANs
+		if len(sans) == 0 {
+			sans = []string{cfg.CommonName}
+		}
+		if err := applySANs(sans, &tmpl.DNSNames, &tmpl.IPAddresses, &tmpl.EmailAddresses, &tmpl.URIs); err != nil {
+			return nil, err
+		}
+	}
+
+	parent, signer := tmpl, priv
+	if cfg.CACert != "" {
+		parent, signer, err = loadCA(cfg.CACert, cfg.CAKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, priv.Public(), signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated certificate: %w", err)
+	}
+	keyPEM, err := encodePrivateKeyPEM(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := "certificate"
+	if isCA {
+		kind = "ca"
+	}
+	fp := sha256.Sum256(der)
+	return &CertBundle{
+		Kind:        kind,
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		Serial:      hex.EncodeToString(cert.SerialNumber.Bytes()),
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		SANs:        certSANs(cert.DNSNames, cert.IPAddresses, cert.EmailAddresses, cert.URIs),
+		KeyType:     cfg.KeyType,
+		Bits:        bits,
+		Fingerprint: hex.EncodeToString(fp[:]),
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		PrivateKey:  keyPEM,
+	}, nil
+}
+
+// genCSR creates a key and a certificate signing request
+func genCSR(cfg CertConfig) (*CertBundle, error) {
+	if cfg.CommonName == "" {
+		return nil, fmt.Errorf("--cn is required")
+	}
+
+	priv, bits, err := genPrivateKey(cfg.KeyType, cfg.Bits)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &x509.CertificateRequest{Subject: certSubject(cfg)}
+	sans := cfg.SANs
+	if len(sans) == 0 {
+		sans = []string{cfg.CommonName}
+	}
+	if err := applySANs(sans, &tmpl.DNSNames, &tmpl.IPAddresses, &tmpl.EmailAddresses, &tmpl.URIs); err != nil {
+		return nil, err
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, tmpl, priv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CSR: %w", err)
+	}
+	keyPEM, err := encodePrivateKeyPEM(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CertBundle{
+		Kind:       "csr",
+		Subject:    tmpl.Subject.String(),
+		SANs:       certSANs(tmpl.DNSNames, tmpl.IPAddresses, tmpl.EmailAddresses, tmpl.URIs),
+		KeyType:    cfg.KeyType,
+		Bits:       bits,
+		CSR:        string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})),
+		PrivateKey: keyPEM,
+	}, nil
+}
+
+// certSANs flattens the SAN fields for display
+func certSANs(dns []string, ips []net.IP, emails []string, uris []*url.URL) []string {
+	out := append([]string{}, dns...)
+	for _, ip := range ips {
+		out = append(out, ip.String())
+	}
+	out = append(out, emails...)
+	for _, u := range uris {
+		out = append(out, u.String())
+	}
+	return out
+}
+
+// encodePrivateKeyPEM encodes a private key as PKCS#8 PEM
+func encodePrivateKeyPEM(priv crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// loadCA reads a CA certificate and its private key from PEM files
+func loadCA(certPath, keyPath string) (*x509.Certificate, crypto.Signer, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, nil, fmt.Errorf("%s does not contain a PEM certificate", certPath)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+	if !cert.IsCA {
+		return nil, nil, fmt.Errorf("%s is not a CA certificate", certPath)
+	}
+
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CA key: %w", err)
+	}
+	signer, err := parsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CA key: %w", err)
+	}
+	return cert, signer, nil
+}
+
+// parsePrivateKeyPEM decodes a PKCS#8, SEC 1 (EC) or PKCS#1 (RSA) private key
+func parsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key of type %T cannot sign", key)
+	}
+	return signer, nil
+}
+
+// certFile is one PEM file of a bundle written with --out
+type certFile struct {
+	path string
+	data string
+	perm os.FileMode
+}
+
+// outputCertBundle writes the bundle to <out>.crt/.csr and <out>.key when
+// --out is set, then prints it as JSON or PEM with a metadata header
+func outputCertBundle(w io.Writer, b *CertBundle, cfg CertConfig) error {
+	if cfg.Out != "" {
+		var files []certFile
+		for _, f := range []certFile{
+			{cfg.Out + ".crt", b.Certificate, 0o644},
+			{cfg.Out + ".csr", b.CSR, 0o644},
+			{cfg.Out + ".key", b.PrivateKey, 0o600},
+		} {
+			if f.data != "" {
+				files = append(files, f)
+			}
+		}
+		// Check every name first so a conflict does not leave a partial bundle
+		for _, f := range files {
+			if err := checkOverwrite(f.path, cfg.Output.Force); err != nil {
+				return err
+			}
+		}
+		for _, f := range files {
+			if err := writeSecretFile(f.path, []byte(f.data), f.perm, cfg.Output.Force); err != nil {
+				return err
+			}
+			b.Files = append(b.Files, f.path)
+		}
+		b.Certificate, b.CSR, b.PrivateKey = "", "", ""
+	}
+
+	if cfg.Output.structured() {
+		var items []SecretItem
+		for _, v := range []struct{ typ, value string }{
+			{"certificate", b.Certificate},
+			{"csr", b.CSR},
+			{"private_key", b.PrivateKey},
+			{"sha256_fingerprint", b.Fingerprint},
+		} {
+			if v.value != "" {
+				items = append(items, newSecretItem(v.typ, v.value, 0))
+			}
+		}
+		return printSecretItems(w, items, cfg.Output)
+	}
+	if cfg.AsJSON {
+		data, err := json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s to JSON: %w", b.Kind, err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(w, "Subject:     %s\n", b.Subject)
+	if b.Issuer != "" {
+		fmt.Fprintf(w, "Issuer:      %s\n", b.Issuer)
+		fmt.Fprintf(w, "Serial:      %s\n", b.Serial)
+		fmt.Fprintf(w, "Valid:       %s to %s\n", b.NotBefore.Format(time.RFC3339), b.NotAfter.Format(time.RFC3339))
+	}
+	if len(b.SANs) > 0 {
+		fmt.Fprintf(w, "SANs:        %s\n", strings.Join(b.SANs, ", "))
+	}
+	fmt.Fprintf(w, "Key:         %s (%d bits)\n", b.KeyType, b.Bits)
+	if b.Fingerprint != "" {
+		fmt.Fprintf(w, "SHA256:      %s\n", b.Fingerprint)
+	}
+	for _, f := range b.Files {
+		fmt.Fprintf(w, "Wrote:       %s\n", f)
+	}
+	if cfg.Out == "" {
+		fmt.Fprintln(w)
+		fmt.Fprint(w, b.Certificate+b.CSR+b.PrivateKey)
+	}
+	return nil
+}
+
+// addCertFlags registers the flags shared by cert, ca and csr
+func addCertFlags(cmd *cobra.Command, defaultDays int) {
+	cmd.Flags().String("cn", "", "subject common name (required)")
+	cmd.Flags().String("org", "", "subject organization")
+	cmd.Flags().String("key-type", "ecdsa", "key type (ecdsa|rsa|ed25519)")
+	cmd.Flags().IntP("bits", "b", 0, "key size: rsa >= 2048 (default 4096), ecdsa 256|384|521")
+	cmd.Flags().StringP("out", "o", "", "write <out>.crt/.csr (0644) and <out>.key (0600) instead of printing PEM")
+	cmd.Flags().Bool("json", false, "output as JSON")
+	addOutputFlags(cmd)
+	if defaultDays > 0 {
+		cmd.Flags().Int("days", defaultDays, "validity period in days")
+	}
+}
+
+func init() {
+	createCmd.AddCommand(createCertCmd)
+	createCmd.AddCommand(createCACmd)
+	createCmd.AddCommand(createCSRCmd)
+
+	addCertFlags(createCertCmd, 365)
+	createCertCmd.Flags().StringSlice("san", nil, "subject alternative names: DNS, IP, email or URI (default: --cn)")
+	createCertCmd.Flags().String("ca-cert", "", "sign with this CA certificate (PEM)")
+	createCertCmd.Flags().String("ca-key", "", "private key of the signing CA (PEM)")
+
+	addCertFlags(createCACmd, 3650)
+
+	addCertFlags(createCSRCmd, 0)
+	createCSRCmd.Flags().StringSlice("san", nil, "subject alternative names: DNS, IP, email or URI (default: --cn)")
+}
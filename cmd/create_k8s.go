@@ -0,0 +1,258 @@
+// cmd/create_k8s.go
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// sealedScopes lists the SealedSecret scopes accepted by --scope
+var sealedScopes = []string{"strict", "namespace-wide", "cluster-wide"}
+
+var (
+	// k8sNameRe matches a DNS-1123 subdomain, the rule for Secret names
+	k8sNameRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	// k8sNamespaceRe matches a DNS-1123 label, the rule for namespaces
+	k8sNamespaceRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	// k8sDataKeyRe matches the characters allowed in a Secret data key
+	k8sDataKeyRe = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+)
+
+// K8sSecretConfig holds configuration for Secret manifest generation
+type K8sSecretConfig struct {
+	Name      string
+	Namespace string
+	Type      string
+	Keys      []SecretKeySpec
+	Scope     string
+	Compose   string
+	AsJSON    bool
+	Output    OutputConfig
+}
+
+// SecretKeySpec pairs a Secret data key with the generator spec for its value
+type SecretKeySpec struct {
+	Key  string
+	Spec string
+}
+
+// K8sSecret is a v1/Secret manifest
+type K8sSecret struct {
+	APIVersion string            `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string            `json:"kind" yaml:"kind"`
+	Metadata   K8sObjectMeta     `json:"metadata" yaml:"metadata"`
+	Type       string            `json:"type" yaml:"type"`
+	Data       map[string]string `json:"data" yaml:"data"`
+}
+
+// K8sObjectMeta is the subset of object metadata keyforge sets
+type K8sObjectMeta struct {
+	Name        string            `json:"name" yaml:"name"`
+	Namespace   string            `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+var createK8sSecretCmd = &cobra.Command{
+	Use:   "k8s-secret",
+	Short: "Create a Kubernetes Secret or Docker Compose secrets",
+	Long: `Generate a value for each --key and emit a v1/Secret manifest with the
+values base64-encoded in its data field, ready for kubectl apply -f -.
+
+Each --key is name=generator, using the generators of 'keyforge fill':
+  --key password=strong:24 --key api-key=key:256:hex --key token=token:32:acme_
+
+With --scope the manifest is annotated for kubeseal, so it can be piped
+straight into 'kubeseal --format yaml' without ever being applied; strict and
+namespace-wide scopes require --namespace.
+
+With --compose DIR each value is written to DIR/<name>_<key> with 0600
+permissions and the matching Docker Compose top-level secrets: block is
+printed instead of the manifest.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		w := cmd.OutOrStdout()
+		cfg, err := getK8sSecretConfigFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if err := validateK8sSecretConfig(cfg); err != nil {
+			return err
+		}
+
+		items := make([]SecretItem, 0, len(cfg.Keys))
+		for _, k := range cfg.Keys {
+			v, bits, err := generateFromSpec(k.Spec)
+			if err != nil {
+				return fmt.Errorf("%s: %w", k.Key, err)
+			}
+			it := newSecretItem(strings.ToLower(strings.SplitN(k.Spec, ":", 2)[0]), v, bits)
+			it.Name = k.Key
+			items = append(items, it)
+		}
+
+		if cfg.Output.structured() {
+			return printSecretItems(w, items, cfg.Output)
+		}
+		if cfg.Compose != "" {
+			return writeComposeSecrets(w, items, cfg)
+		}
+		secret := newK8sSecret(items, cfg)
+		if cfg.AsJSON {
+			return printJSON(w, secret, "Secret")
+		}
+		return printYAML(w, secret, "Secret")
+	},
+}
+
+// getK8sSecretConfigFromFlags extracts configuration from command flags
+func getK8sSecretConfigFromFlags(cmd *cobra.Command) (K8sSecretConfig, error) {
+	name, _ := cmd.Flags().GetString("name")
+	namespace, _ := cmd.Flags().GetString("namespace")
+	secretType, _ := cmd.Flags().GetString("type")
+	keys, _ := cmd.Flags().GetStringArray("key")
+	scope, _ := cmd.Flags().GetString("scope")
+	compose, _ := cmd.Flags().GetString("compose")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	specs, err := parseSecretKeySpecs(keys)
+	if err != nil {
+		return K8sSecretConfig{}, err
+	}
+	return K8sSecretConfig{
+		Name:      name,
+		Namespace: namespace,
+		Type:      secretType,
+		Keys:      specs,
+		Scope:     strings.ToLower(scope),
+		Compose:   compose,
+		AsJSON:    asJSON,
+		Output:    getOutputConfigFromFlags(cmd),
+	}, nil
+}
+
+// parseSecretKeySpecs splits each name=generator pair
+func parseSecretKeySpecs(keys []string) ([]SecretKeySpec, error) {
+	specs := make([]SecretKeySpec, 0, len(keys))
+	seen := map[string]bool{}
+	for _, kv := range keys {
+		key, spec, ok := strings.Cut(kv, "=")
+		key, spec = strings.TrimSpace(key), strings.TrimSpace(spec)
+		if !ok || key == "" || spec == "" {
+			return nil, fmt.Errorf("invalid --key %q (want name=generator, e.g. password=strong:24)", kv)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate key: %s", key)
+		}
+		seen[key] = true
+		specs = append(specs, SecretKeySpec{Key: key, Spec: spec})
+	}
+	return specs, nil
+}
+
+// validateK8sSecretConfig checks names against the Kubernetes rules before
+// anything is generated
+func validateK8sSecretConfig(cfg K8sSecretConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("--name is required")
+	}
+	if len(cfg.Name) > 253 || !k8sNameRe.MatchString(cfg.Name) {
+		return fmt.Errorf("invalid name %q: must be lowercase letters, digits, '-' and '.'", cfg.Name)
+	}
+	if cfg.Namespace != "" && (len(cfg.Namespace) > 63 || !k8sNamespaceRe.MatchString(cfg.Namespace)) {
+		return fmt.Errorf("invalid namespace %q: must be lowercase letters, digits and '-'", cfg.Namespace)
+	}
+	if len(cfg.Keys) == 0 {
+		return fmt.Errorf("at least one --key is required")
+	}
+	for _, k := range cfg.Keys {
+		if len(k.Key) > 253 || !k8sDataKeyRe.MatchString(k.Key) || k.Key == "." || k.Key == ".." {
+			return fmt.Errorf("invalid key %q: must be letters, digits, '-', '_' and '.'", k.Key)
+		}
+	}
+
+	if cfg.Scope != "" {
+		if !slices.Contains(sealedScopes, cfg.Scope) {
+			return fmt.Errorf("unsupported scope %q (want strict, namespace-wide or cluster-wide)", cfg.Scope)
+		}
+		if cfg.Scope != "cluster-wide" && cfg.Namespace == "" {
+			return fmt.Errorf("--scope %s requires --namespace", cfg.Scope)
+		}
+	}
+	if cfg.Compose != "" {
+		if cfg.Output.structured() || cfg.AsJSON {
+			return fmt.Errorf("--compose cannot be combined with --json, --output, --template or --out-dir")
+		}
+		if cfg.Scope != "" {
+			return fmt.Errorf("--compose cannot be combined with --scope")
+		}
+	}
+	return nil
+}
+
+// newK8sSecret builds the manifest, base64-encoding each value
+func newK8sSecret(items []SecretItem, cfg K8sSecretConfig) K8sSecret {
+	data := make(map[string]string, len(items))
+	for _, it := range items {
+		data[it.Name] = base64.StdEncoding.EncodeToString([]byte(it.Value))
+	}
+	meta := K8sObjectMeta{Name: cfg.Name, Namespace: cfg.Namespace}
+	switch cfg.Scope {
+	case "namespace-wide", "cluster-wide":
+		meta.Annotations = map[string]string{"sealedsecrets.bitnami.com/" + cfg.Scope: "true"}
+	}
+	return K8sSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   meta,
+		Type:       cfg.Type,
+		Data:       data,
+	}
+}
+
+// writeComposeSecrets writes each value to its own 0600 file and prints the
+// Compose secrets: block that references them
+func writeComposeSecrets(w io.Writer, items []SecretItem, cfg K8sSecretConfig) error {
+	if err := os.MkdirAll(cfg.Compose, 0o700); err != nil {
+		return fmt.Errorf("failed to create compose secrets directory: %w", err)
+	}
+	// Check every name first so a conflict does not leave a partial set
+	for _, it := range items {
+		if err := checkOverwrite(filepath.Join(cfg.Compose, cfg.Name+"_"+it.Name), cfg.Output.Force); err != nil {
+			return err
+		}
+	}
+
+	dir := filepath.ToSlash(cfg.Compose)
+	if !filepath.IsAbs(cfg.Compose) && !strings.HasPrefix(dir, ".") {
+		dir = "./" + dir
+	}
+	secrets := make(map[string]map[string]string, len(items))
+	for _, it := range items {
+		name := cfg.Name + "_" + it.Name
+		if err := writeSecretFile(filepath.Join(cfg.Compose, name), []byte(it.Value), 0o600, cfg.Output.Force); err != nil {
+			return err
+		}
+		secrets[name] = map[string]string{"file": strings.TrimSuffix(dir, "/") + "/" + name}
+	}
+	return printYAML(w, map[string]any{"secrets": secrets}, "compose secrets")
+}
+
+func init() {
+	createCmd.AddCommand(createK8sSecretCmd)
+
+	createK8sSecretCmd.Flags().String("name", "", "Secret name (required)")
+	createK8sSecretCmd.Flags().StringP("namespace", "n", "", "Secret namespace")
+	createK8sSecretCmd.Flags().String("type", "Opaque", "Secret type")
+	createK8sSecretCmd.Flags().StringArrayP("key", "k", nil, "data key and generator as name=generator (repeatable)")
+	createK8sSecretCmd.Flags().String("scope", "", "annotate for kubeseal ("+strings.Join(sealedScopes, "|")+")")
+	createK8sSecretCmd.Flags().String("compose", "", "write Docker Compose secret files to this directory instead")
+	createK8sSecretCmd.Flags().Bool("json", false, "output the manifest as JSON")
+	addOutputFlags(createK8sSecretCmd)
+}
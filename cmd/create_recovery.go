@@ -0,0 +1,189 @@
+// cmd/create_recovery.go
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// recoveryAlphabet omits characters that are easily confused when read or
+// typed back (0/o, 1/i/l) as well as u, as in Crockford's base32
+const recoveryAlphabet = "23456789abcdefghjkmnpqrstvwxyz"
+
+// RecoveryConfig holds configuration for recovery code generation
+type RecoveryConfig struct {
+	Count   int
+	Format  string
+	Hash    string
+	HashOut string
+	AsJSON  bool
+	Output  OutputConfig
+}
+
+// RecoveryCode is a recovery code with its optional storage hash
+type RecoveryCode struct {
+	Code string `json:"code"`
+	Hash string `json:"hash,omitempty"`
+}
+
+var createRecoveryCmd = &cobra.Command{
+	Use:   "recovery-codes",
+	Short: "Create a set of MFA recovery/backup codes",
+	Long: `Generate a set of unique, human-readable MFA recovery codes.
+
+Each 'x' in --format is replaced by a character from an unambiguous alphabet
+(no 0/o, 1/i/l or u); any other character is kept as a separator. With --hash
+each code is also hashed (bcrypt or argon2id, exactly as printed) for
+server-side storage; --hash-out writes those hashes to a 0600 file so the
+printable sheet can go to the user and the hashes to the database.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getRecoveryConfigFromFlags(cmd)
+		if cfg.Count <= 0 {
+			return fmt.Errorf("count must be positive, got: %d", cfg.Count)
+		}
+		slots := strings.Count(cfg.Format, "x")
+		if slots < 6 {
+			return fmt.Errorf("format needs at least 6 'x' placeholders, got: %d", slots)
+		}
+		if cfg.Hash != "" {
+			if err := validateHashAlgorithm(cfg.Hash); err != nil {
+				return err
+			}
+		}
+
+		codes, err := genRecoveryCodes(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+
+		if cfg.HashOut != "" {
+			var b strings.Builder
+			for _, c := range codes {
+				b.WriteString(c.Hash + "\n")
+			}
+			if err := writeSecretFile(cfg.HashOut, []byte(b.String()), 0o600, cfg.Output.Force); err != nil {
+				return err
+			}
+		}
+		return printRecoveryCodes(cmd.OutOrStdout(), codes, cfg)
+	},
+}
+
+// getRecoveryConfigFromFlags extracts configuration from command flags
+func getRecoveryConfigFromFlags(cmd *cobra.Command) RecoveryConfig {
+	count, _ := cmd.Flags().GetInt("count")
+	format, _ := cmd.Flags().GetString("format")
+	hash, _ := cmd.Flags().GetString("hash")
+	hashOut, _ := cmd.Flags().GetString("hash-out")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	if hashOut != "" && hash == "" {
+		hash = "bcrypt"
+	}
+	return RecoveryConfig{
+		Count:   count,
+		Format:  strings.ToLower(format),
+		Hash:    strings.ToLower(hash),
+		HashOut: hashOut,
+		AsJSON:  asJSON,
+		Output:  getOutputConfigFromFlags(cmd),
+	}
+}
+
+// genRecoveryCode fills every 'x' in format from the recovery alphabet
+func genRecoveryCode(format string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(format))
+	for i := 0; i < len(format); i++ {
+		if format[i] != 'x' {
+			b.WriteByte(format[i])
+			continue
+		}
+		ch, err := randChoice(recoveryAlphabet)
+		if err != nil {
+			return "", fmt.Errorf("failed to select random character: %w", err)
+		}
+		b.WriteByte(ch)
+	}
+	return b.String(), nil
+}
+
+// genRecoveryCodes generates a set of distinct codes, hashing them if asked
+func genRecoveryCodes(cfg RecoveryConfig) ([]RecoveryCode, error) {
+	bits := float64(strings.Count(cfg.Format, "x")) * math.Log2(float64(len(recoveryAlphabet)))
+	if math.Exp2(bits) < float64(cfg.Count) {
+		return nil, fmt.Errorf("format %q cannot produce %d distinct codes", cfg.Format, cfg.Count)
+	}
+
+	seen := make(map[string]struct{}, cfg.Count)
+	codes := make([]RecoveryCode, 0, cfg.Count)
+	for len(codes) < cfg.Count {
+		code, err := genRecoveryCode(cfg.Format)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := seen[code]; dup {
+			continue
+		}
+		seen[code] = struct{}{}
+
+		rc := RecoveryCode{Code: code}
+		if cfg.Hash != "" {
+			if rc.Hash, err = hashPassword(cfg.Hash, code); err != nil {
+				return nil, err
+			}
+		}
+		codes = append(codes, rc)
+	}
+	return codes, nil
+}
+
+// printRecoveryCodes outputs the printable sheet, or JSON
+func printRecoveryCodes(w io.Writer, codes []RecoveryCode, cfg RecoveryConfig) error {
+	if cfg.Output.structured() {
+		bits := float64(strings.Count(cfg.Format, "x")) * math.Log2(float64(len(recoveryAlphabet)))
+		items := make([]SecretItem, 0, len(codes))
+		for _, c := range codes {
+			it := newSecretItem("recovery_code", c.Code, bits)
+			if cfg.HashOut == "" {
+				it.Hash = c.Hash
+			}
+			items = append(items, it)
+		}
+		return printSecretItems(w, items, cfg.Output)
+	}
+	if cfg.AsJSON {
+		return printJSON(w, codes, "recovery codes")
+	}
+
+	fmt.Fprintln(w, "Recovery codes - each code can be used once. Keep this sheet somewhere safe.")
+	fmt.Fprintln(w)
+	width := len(fmt.Sprint(len(codes)))
+	for i, c := range codes {
+		fmt.Fprintf(w, "  %*d. %s\n", width, i+1, c.Code)
+	}
+
+	if cfg.Hash != "" && cfg.HashOut == "" {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Hashes (%s):\n", cfg.Hash)
+		for _, c := range codes {
+			fmt.Fprintln(w, c.Hash)
+		}
+	}
+	return nil
+}
+
+func init() {
+	createCmd.AddCommand(createRecoveryCmd)
+
+	createRecoveryCmd.Flags().IntP("count", "c", 10, "number of codes to generate")
+	createRecoveryCmd.Flags().String("format", "xxxx-xxxx", "code layout; each 'x' is a random character")
+	createRecoveryCmd.Flags().String("hash", "", "also hash each code for storage ("+strings.Join(hashAlgorithms, "|")+")")
+	createRecoveryCmd.Flags().String("hash-out", "", "write the hashes to this file with 0600 permissions (implies --hash bcrypt)")
+	createRecoveryCmd.Flags().Bool("json", false, "output codes (and hashes) as JSON")
+	addOutputFlags(createRecoveryCmd)
+}
@@ -4,7 +4,10 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -13,6 +16,10 @@ import (
 type SetConfig struct {
 	Count  int
 	AsJSON bool
+	Output OutputConfig
+	Title  string
+	QR     bool
+	Paper  string
 }
 
 // PasswordSet represents a collection of passwords/keys by type
@@ -24,6 +31,12 @@ type PasswordSet struct {
 	WEP256  []string `json:"256wep"`
 }
 
+// setSection is one named group of a password set
+type setSection struct {
+	name      string
+	passwords []string
+}
+
 var createSetCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Create a set (grid) of passwords/keys",
@@ -31,16 +44,44 @@ var createSetCmd = &cobra.Command{
 This creates multiple instances of each supported password/key type:
 - Easy (memorable) passwords
 - Strong (cryptographically secure) passwords  
-- WEP keys (64-bit, 128-bit, and 256-bit)`,
+- WEP keys (64-bit, 128-bit, and 256-bit)
+
+--output html or pdf produces a printable credential sheet: each value in a
+font and colors that keep 0/O and l/1 apart, spelled out with the NATO
+alphabet, and with --qr a QR code next to it.`,
+	Annotations: map[string]string{sheetAnnotation: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := getSetConfigFromFlags(cmd)
+		sheet := slices.Contains(sheetFormats, cfg.Output.Format)
+		if !sheet {
+			for _, f := range []string{"qr", "title", "paper"} {
+				if cmd.Flags().Changed(f) {
+					return fmt.Errorf("--%s requires --output html or pdf", f)
+				}
+			}
+		}
+		if _, ok := pdfPaperSizes[cfg.Paper]; !ok {
+			return fmt.Errorf("unsupported paper %q (want letter or a4)", cfg.Paper)
+		}
+		if cfg.Output.Format == "pdf" {
+			if _, err := pdfEncode(cfg.Title); err != nil {
+				return fmt.Errorf("invalid title: %w", err)
+			}
+		}
 		
 		passwordSet, err := generatePasswordSet(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to generate password set: %w", err)
 		}
 		
-		return printPasswordSet(passwordSet, cfg.AsJSON)
+		w := cmd.OutOrStdout()
+		if sheet {
+			return printSheet(w, newPasswordSetSheet(passwordSet, cfg.Title, cfg.QR), cfg.Output.Format, cfg.Paper)
+		}
+		if cfg.Output.structured() {
+			return printSecretItems(w, passwordSetItems(passwordSet), cfg.Output)
+		}
+		return printPasswordSet(w, passwordSet, cfg.AsJSON)
 	},
 }
 
@@ -48,10 +89,17 @@ This creates multiple instances of each supported password/key type:
 func getSetConfigFromFlags(cmd *cobra.Command) SetConfig {
 	count, _ := cmd.Flags().GetInt("count")
 	asJSON, _ := cmd.Flags().GetBool("json")
+	title, _ := cmd.Flags().GetString("title")
+	qr, _ := cmd.Flags().GetBool("qr")
+	paper, _ := cmd.Flags().GetString("paper")
 	
 	return SetConfig{
 		Count:  count,
 		AsJSON: asJSON,
+		Output: getOutputConfigFromFlags(cmd),
+		Title:  title,
+		QR:     qr,
+		Paper:  strings.ToLower(paper),
 	}
 }
 
@@ -107,38 +155,50 @@ func generatePasswordSet(cfg SetConfig) (*PasswordSet, error) {
 	return set, nil
 }
 
+// passwordSetItems flattens the set into items, section by section
+func passwordSetItems(set *PasswordSet) []SecretItem {
+	var items []SecretItem
+	items = append(items, newSecretItems("easy", set.Easy, easyEntropyBits(12))...)
+	items = append(items, newSecretItems("strong", set.Strong, strongEntropyBits(20))...)
+	items = append(items, newSecretItems("wep64", set.WEP64, 5*8)...)
+	items = append(items, newSecretItems("wep128", set.WEP128, 13*8)...)
+	return append(items, newSecretItems("wep256", set.WEP256, 29*8)...)
+}
+
+// passwordSetSections returns the set's groups in display order
+func passwordSetSections(set *PasswordSet) []setSection {
+	return []setSection{
+		{"easy", set.Easy},
+		{"strong", set.Strong},
+		{"64wep", set.WEP64},
+		{"128wep", set.WEP128},
+		{"256wep", set.WEP256},
+	}
+}
+
 // printPasswordSet outputs the password set in the requested format
-func printPasswordSet(set *PasswordSet, asJSON bool) error {
+func printPasswordSet(w io.Writer, set *PasswordSet, asJSON bool) error {
 	if asJSON {
 		data, err := json.MarshalIndent(set, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal password set to JSON: %w", err)
 		}
-		fmt.Println(string(data))
+		fmt.Fprintln(w, string(data))
 		return nil
 	}
 	
 	// Plain text output with consistent ordering
-	sections := []struct {
-		name     string
-		passwords []string
-	}{
-		{"easy", set.Easy},
-		{"strong", set.Strong},
-		{"64wep", set.WEP64},
-		{"128wep", set.WEP128},
-		{"256wep", set.WEP256},
-	}
+	sections := passwordSetSections(set)
 	
 	for i, section := range sections {
-		fmt.Printf("== %s ==\n", section.name)
+		fmt.Fprintf(w, "== %s ==\n", section.name)
 		for _, password := range section.passwords {
-			fmt.Println(password)
+			fmt.Fprintln(w, password)
 		}
 		
 		// Add blank line between sections (except after the last one)
 		if i < len(sections)-1 {
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
 	}
 	
@@ -244,4 +304,8 @@ func init() {
 	// Add flags
 	createSetCmd.Flags().IntP("count", "c", 4, "number of passwords/keys to generate for each type")
 	createSetCmd.Flags().Bool("json", false, "output as JSON")
+	createSetCmd.Flags().String("title", "Credentials", "heading of the printable sheet (html/pdf)")
+	createSetCmd.Flags().Bool("qr", false, "add a QR code for each value to the printable sheet (html/pdf)")
+	createSetCmd.Flags().String("paper", "letter", "PDF paper size (letter|a4)")
+	addOutputFlags(createSetCmd)
 }
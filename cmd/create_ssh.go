@@ -0,0 +1,234 @@
+// cmd/create_ssh.go
+package cmd
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHConfig holds configuration for SSH keypair generation
+type SSHConfig struct {
+	Type    string
+	Bits    int
+	Comment string
+	Encrypt bool
+	Out     string
+	AsJSON  bool
+	Output  OutputConfig
+}
+
+// SSHKeyPair is a generated OpenSSH keypair
+type SSHKeyPair struct {
+	Type        string `json:"type"`
+	Bits        int    `json:"bits"`
+	Comment     string `json:"comment,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	PublicKey   string `json:"public_key"`
+	PrivateKey  string `json:"private_key,omitempty"`
+	Passphrase  string `json:"passphrase,omitempty"`
+	PrivatePath string `json:"private_path,omitempty"`
+	PublicPath  string `json:"public_path,omitempty"`
+}
+
+var createSSHCmd = &cobra.Command{
+	Use:   "ssh",
+	Short: "Create an SSH keypair (ed25519, ecdsa, rsa)",
+	Long: `Generate an SSH keypair with the private key in OpenSSH format and the
+public key as an authorized_keys line, plus its SHA256 fingerprint.
+
+With --encrypt the private key is protected by a freshly generated strong
+passphrase, which is printed once alongside the key. With --out the private
+key is written with 0600 permissions and the public key to <out>.pub.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getSSHConfigFromFlags(cmd)
+		if cfg.Out != "" {
+			// Check both names first so a conflict does not leave half a keypair
+			for _, path := range []string{cfg.Out, cfg.Out + ".pub"} {
+				if err := checkOverwrite(path, cfg.Output.Force); err != nil {
+					return err
+				}
+			}
+		}
+
+		pair, err := genSSHKeyPair(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to generate SSH keypair: %w", err)
+		}
+
+		if cfg.Out != "" {
+			if err := writeSecretFile(cfg.Out, []byte(pair.PrivateKey), 0o600, cfg.Output.Force); err != nil {
+				return err
+			}
+			if err := writeSecretFile(cfg.Out+".pub", []byte(pair.PublicKey+"\n"), 0o644, cfg.Output.Force); err != nil {
+				return err
+			}
+			pair.PrivateKey = ""
+			pair.PrivatePath, pair.PublicPath = cfg.Out, cfg.Out+".pub"
+		}
+		if cfg.Output.structured() {
+			return printSecretItems(cmd.OutOrStdout(), sshKeyPairItems(pair), cfg.Output)
+		}
+		return printSSHKeyPair(cmd.OutOrStdout(), pair, cfg.AsJSON)
+	},
+}
+
+// getSSHConfigFromFlags extracts configuration from command flags
+func getSSHConfigFromFlags(cmd *cobra.Command) SSHConfig {
+	keyType, _ := cmd.Flags().GetString("type")
+	bits, _ := cmd.Flags().GetInt("bits")
+	comment, _ := cmd.Flags().GetString("comment")
+	encrypt, _ := cmd.Flags().GetBool("encrypt")
+	out, _ := cmd.Flags().GetString("out")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	return SSHConfig{
+		Type:    strings.ToLower(keyType),
+		Bits:    bits,
+		Comment: comment,
+		Encrypt: encrypt,
+		Out:     out,
+		AsJSON:  asJSON,
+		Output:  getOutputConfigFromFlags(cmd),
+	}
+}
+
+// genPrivateKey generates a private key of the requested type and size,
+// returning the effective size in bits
+func genPrivateKey(keyType string, bits int) (crypto.Signer, int, error) {
+	switch keyType {
+	case "ed25519":
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		return priv, 256, err
+	case "ecdsa":
+		var curve elliptic.Curve
+		switch bits {
+		case 0, 256:
+			curve, bits = elliptic.P256(), 256
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, 0, fmt.Errorf("ecdsa bits must be 256, 384 or 521, got: %d", bits)
+		}
+		priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+		return priv, bits, err
+	case "rsa":
+		if bits == 0 {
+			bits = 4096
+		}
+		if bits < 2048 {
+			return nil, 0, fmt.Errorf("rsa bits must be at least 2048, got: %d", bits)
+		}
+		priv, err := rsa.GenerateKey(rand.Reader, bits)
+		return priv, bits, err
+	default:
+		return nil, 0, fmt.Errorf("unsupported key type %q (want ed25519, ecdsa or rsa)", keyType)
+	}
+}
+
+// genSSHKeyPair generates and serialises an OpenSSH keypair
+func genSSHKeyPair(cfg SSHConfig) (*SSHKeyPair, error) {
+	priv, bits, err := genPrivateKey(cfg.Type, cfg.Bits)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := ssh.NewPublicKey(priv.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive public key: %w", err)
+	}
+
+	pair := &SSHKeyPair{
+		Type:        cfg.Type,
+		Bits:        bits,
+		Comment:     cfg.Comment,
+		Fingerprint: ssh.FingerprintSHA256(pub),
+		PublicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))),
+	}
+	if cfg.Comment != "" {
+		pair.PublicKey += " " + cfg.Comment
+	}
+
+	var block *pem.Block
+	if cfg.Encrypt {
+		pair.Passphrase, err = genStrongWithError(24)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate passphrase: %w", err)
+		}
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, cfg.Comment, []byte(pair.Passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(priv, cfg.Comment)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	pair.PrivateKey = string(pem.EncodeToMemory(block))
+
+	return pair, nil
+}
+
+// sshKeyPairItems lists the keypair's values for --output; a private key
+// written to --out is left out
+func sshKeyPairItems(pair *SSHKeyPair) []SecretItem {
+	var items []SecretItem
+	if pair.PrivateKey != "" {
+		items = append(items, newSecretItem("ssh_private_key", pair.PrivateKey, 0))
+	}
+	items = append(items,
+		newSecretItem("ssh_public_key", pair.PublicKey, 0),
+		newSecretItem("ssh_fingerprint", pair.Fingerprint, 0))
+	if pair.Passphrase != "" {
+		items = append(items, newSecretItem("ssh_passphrase", pair.Passphrase, strongEntropyBits(len(pair.Passphrase))))
+	}
+	return items
+}
+
+// printSSHKeyPair outputs the keypair in the requested format
+func printSSHKeyPair(w io.Writer, pair *SSHKeyPair, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(pair, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal SSH keypair to JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(w, "Fingerprint: %s\n", pair.Fingerprint)
+	if pair.Passphrase != "" {
+		fmt.Fprintf(w, "Passphrase:  %s\n", pair.Passphrase)
+	}
+	if pair.PrivatePath != "" {
+		fmt.Fprintf(w, "Private key: %s\n", pair.PrivatePath)
+		fmt.Fprintf(w, "Public key:  %s\n", pair.PublicPath)
+		return nil
+	}
+	fmt.Fprintln(w)
+	fmt.Fprint(w, pair.PrivateKey)
+	fmt.Fprintln(w, pair.PublicKey)
+	return nil
+}
+
+func init() {
+	createCmd.AddCommand(createSSHCmd)
+
+	createSSHCmd.Flags().StringP("type", "t", "ed25519", "key type (ed25519|ecdsa|rsa)")
+	createSSHCmd.Flags().IntP("bits", "b", 0, "key size: rsa >= 2048 (default 4096), ecdsa 256|384|521")
+	createSSHCmd.Flags().StringP("comment", "C", "", "comment stored in the key and public line")
+	createSSHCmd.Flags().Bool("encrypt", false, "encrypt the private key with a generated passphrase")
+	createSSHCmd.Flags().StringP("out", "o", "", "write private key here (0600) and public key to <out>.pub")
+	createSSHCmd.Flags().Bool("json", false, "output as JSON")
+	addOutputFlags(createSSHCmd)
+}
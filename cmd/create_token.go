@@ -0,0 +1,177 @@
+// cmd/create_token.go
+package cmd
+
+import (
+	"fmt"
+	"hash/crc32"
+	"math"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// tokenChecksumLen is the width of a base62-encoded CRC32 (62^6 > 2^32)
+	tokenChecksumLen = 6
+)
+
+// TokenConfig holds configuration for API token generation
+type TokenConfig struct {
+	Config
+	Prefix   string
+	Bytes    int
+	Checksum string
+}
+
+var createTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Create a prefixed API token with an offline-verifiable checksum",
+	Long: `Generate GitHub-style scannable API tokens: a fixed prefix, a base62 body
+carrying --bytes of randomness and a 6-character base62 CRC32 of the body.
+
+The prefix lets secret scanners find leaked tokens and the checksum lets
+them (and 'keyforge verify token') reject random lookalikes offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getTokenConfigFromFlags(cmd)
+		if cfg.Bytes < 16 {
+			return fmt.Errorf("bytes must be at least 16, got: %d", cfg.Bytes)
+		}
+		if cfg.Checksum != "crc32" && cfg.Checksum != "none" {
+			return fmt.Errorf("unsupported checksum %q (want crc32 or none)", cfg.Checksum)
+		}
+		if strings.IndexFunc(cfg.Prefix, func(r rune) bool { return !isTokenPrefixRune(r) }) >= 0 {
+			return fmt.Errorf("prefix may only contain letters, digits and underscores: %q", cfg.Prefix)
+		}
+
+		bodyLen := tokenBodyLen(cfg.Bytes)
+		bits := float64(bodyLen) * math.Log2(float64(len(base62Alphabet)))
+		results, err := generateBatch(cmd, cfg.Config, bits, func() (string, error) {
+			return genTokenWithError(cfg.Prefix, bodyLen, cfg.Checksum == "crc32")
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate token: %w", err)
+		}
+		return printSecretResults(cmd.OutOrStdout(), results, "token", bits, cfg.Config)
+	},
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify generated secrets offline",
+}
+
+var verifyTokenCmd = &cobra.Command{
+	Use:   "token [token]",
+	Short: "Validate the checksum of a token created by 'create token'",
+	Long: `Validate a token's CRC32 checksum offline. The prefix is everything up to
+and including the last underscore unless --prefix is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := readSecretArg(cmd, args, "token")
+		if err != nil {
+			return err
+		}
+		prefix, _ := cmd.Flags().GetString("prefix")
+		if err := verifyToken(token, prefix); err != nil {
+			return err
+		}
+		fmt.Println("valid")
+		return nil
+	},
+}
+
+// getTokenConfigFromFlags extracts configuration from command flags
+func getTokenConfigFromFlags(cmd *cobra.Command) TokenConfig {
+	prefix, _ := cmd.Flags().GetString("prefix")
+	bytes, _ := cmd.Flags().GetInt("bytes")
+	checksum, _ := cmd.Flags().GetString("checksum")
+
+	return TokenConfig{
+		Config:   getConfigFromFlags(cmd),
+		Prefix:   prefix,
+		Bytes:    bytes,
+		Checksum: strings.ToLower(checksum),
+	}
+}
+
+// tokenBodyLen returns how many base62 characters carry n bytes of entropy
+func tokenBodyLen(n int) int {
+	return int(math.Ceil(float64(n*8) / math.Log2(float64(len(base62Alphabet)))))
+}
+
+// genTokenWithError builds prefix + base62 body (+ base62 CRC32 of the body)
+func genTokenWithError(prefix string, bodyLen int, checksum bool) (string, error) {
+	var b strings.Builder
+	b.Grow(len(prefix) + bodyLen + tokenChecksumLen)
+	b.WriteString(prefix)
+
+	for i := 0; i < bodyLen; i++ {
+		ch, err := randChoice(base62Alphabet)
+		if err != nil {
+			return "", fmt.Errorf("failed to select random character: %w", err)
+		}
+		b.WriteByte(ch)
+	}
+
+	if checksum {
+		b.WriteString(tokenChecksum(b.String()[len(prefix):]))
+	}
+	return b.String(), nil
+}
+
+// tokenChecksum returns the CRC32 of body as fixed-width base62
+func tokenChecksum(body string) string {
+	sum := crc32.ChecksumIEEE([]byte(body))
+	out := make([]byte, tokenChecksumLen)
+	for i := tokenChecksumLen - 1; i >= 0; i-- {
+		out[i] = base62Alphabet[sum%62]
+		sum /= 62
+	}
+	return string(out)
+}
+
+// verifyToken checks the trailing checksum of a token against its body
+func verifyToken(token, prefix string) error {
+	if prefix == "" {
+		prefix = token[:strings.LastIndexByte(token, '_')+1]
+	} else if !strings.HasPrefix(token, prefix) {
+		return fmt.Errorf("token does not start with prefix %q", prefix)
+	}
+
+	rest := token[len(prefix):]
+	if len(rest) <= tokenChecksumLen {
+		return fmt.Errorf("token is too short to carry a checksum")
+	}
+	if strings.IndexFunc(rest, func(r rune) bool { return !strings.ContainsRune(base62Alphabet, r) }) >= 0 {
+		return fmt.Errorf("token body is not base62")
+	}
+
+	body, sum := rest[:len(rest)-tokenChecksumLen], rest[len(rest)-tokenChecksumLen:]
+	if tokenChecksum(body) != sum {
+		return fmt.Errorf("invalid token checksum")
+	}
+	return nil
+}
+
+// isTokenPrefixRune reports whether r may appear in a token prefix
+func isTokenPrefixRune(r rune) bool {
+	return r == '_' || (r < 128 && strings.ContainsRune(base62Alphabet, r))
+}
+
+func init() {
+	createCmd.AddCommand(createTokenCmd)
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.AddCommand(verifyTokenCmd)
+
+	createTokenCmd.Flags().StringP("prefix", "p", "kf_", "token prefix used by secret scanners (letters, digits, underscores)")
+	createTokenCmd.Flags().IntP("bytes", "b", 32, "bytes of randomness in the token body (minimum 16)")
+	createTokenCmd.Flags().String("checksum", "crc32", "checksum appended to the body (crc32|none)")
+	createTokenCmd.Flags().IntP("count", "c", 1, "number of tokens to generate")
+	createTokenCmd.Flags().Bool("json", false, "output as JSON array")
+	addOutputFlags(createTokenCmd)
+	addUniqueFlags(createTokenCmd)
+
+	verifyTokenCmd.Flags().StringP("prefix", "p", "", "expected prefix (default: up to the last underscore)")
+	verifyTokenCmd.Flags().Bool("stdin", false, "read token from STDIN")
+}
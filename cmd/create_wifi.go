@@ -0,0 +1,264 @@
+// cmd/create_wifi.go
+package cmd
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// WiFiConfig holds configuration for Wi-Fi credential provisioning
+type WiFiConfig struct {
+	SSID     string
+	Security string
+	Style    string
+	Length   int
+	Bits     int
+	Hidden   bool
+	QR       bool
+	QRPNG    string
+	AsJSON   bool
+	Output   OutputConfig
+}
+
+// WiFiCredential is a generated network credential and its join payload
+type WiFiCredential struct {
+	SSID       string `json:"ssid"`
+	Security   string `json:"security"`
+	Passphrase string `json:"passphrase,omitempty"`
+	PSK        string `json:"psk,omitempty"`
+	WEPKey     string `json:"wep_key,omitempty"`
+	QRPayload  string `json:"qr_payload"`
+	QRPNG      string `json:"qr_png,omitempty"`
+}
+
+const wepWarning = `WARNING: WEP is broken and can be cracked in minutes.
+         Use it only for legacy devices on an isolated network.`
+
+var createWiFiCmd = &cobra.Command{
+	Use:   "wifi",
+	Short: "Create Wi-Fi credentials (WPA2/WPA3 passphrase, PSK, join QR code)",
+	Long: `Generate Wi-Fi network credentials and a WIFI: join payload for guests.
+
+WPA2 and WPA3 get an 8-63 character printable ASCII passphrase; WPA2 also
+gets the derived 256-bit PSK (PBKDF2-HMAC-SHA1 over the SSID, 4096 rounds)
+for devices that take a raw key. WPA3-SAE uses the passphrase directly.
+WEP is supported for legacy hardware only and prints a warning.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getWiFiConfigFromFlags(cmd)
+		if err := validateWiFiConfig(cfg); err != nil {
+			return err
+		}
+
+		cred, err := genWiFiCredential(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to generate Wi-Fi credential: %w", err)
+		}
+		if cfg.Security == "wep" {
+			fmt.Fprintln(os.Stderr, wepWarning)
+		}
+
+		if cfg.QRPNG != "" {
+			if err := writeQRPNG(cfg.QRPNG, cred.QRPayload, cfg.Output.Force); err != nil {
+				return err
+			}
+			cred.QRPNG = cfg.QRPNG
+		}
+		return printWiFiCredential(cmd.OutOrStdout(), cred, cfg)
+	},
+}
+
+// getWiFiConfigFromFlags extracts configuration from command flags
+func getWiFiConfigFromFlags(cmd *cobra.Command) WiFiConfig {
+	ssid, _ := cmd.Flags().GetString("ssid")
+	security, _ := cmd.Flags().GetString("security")
+	style, _ := cmd.Flags().GetString("style")
+	length, _ := cmd.Flags().GetInt("length")
+	bits, _ := cmd.Flags().GetInt("bits")
+	hidden, _ := cmd.Flags().GetBool("hidden")
+	qr, _ := cmd.Flags().GetBool("qr")
+	qrPNG, _ := cmd.Flags().GetString("qr-png")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	return WiFiConfig{
+		SSID:     ssid,
+		Security: strings.ToLower(security),
+		Style:    strings.ToLower(style),
+		Length:   length,
+		Bits:     bits,
+		Hidden:   hidden,
+		QR:       qr,
+		QRPNG:    qrPNG,
+		AsJSON:   asJSON,
+		Output:   getOutputConfigFromFlags(cmd),
+	}
+}
+
+// validateWiFiConfig checks the SSID, security mode and passphrase options
+func validateWiFiConfig(cfg WiFiConfig) error {
+	if cfg.SSID == "" || len(cfg.SSID) > 32 {
+		return fmt.Errorf("ssid must be 1-32 bytes, got: %d", len(cfg.SSID))
+	}
+	switch cfg.Security {
+	case "wpa2", "wpa3":
+		if cfg.Length < 8 || cfg.Length > 63 {
+			return fmt.Errorf("passphrase length must be 8-63, got: %d", cfg.Length)
+		}
+		if cfg.Style != "strong" && cfg.Style != "easy" {
+			return fmt.Errorf("unsupported style %q (want strong or easy)", cfg.Style)
+		}
+	case "wep":
+		if cfg.Bits != 64 && cfg.Bits != 128 {
+			return fmt.Errorf("wep bits must be 64 or 128, got: %d", cfg.Bits)
+		}
+	default:
+		return fmt.Errorf("unsupported security %q (want wpa2, wpa3 or wep)", cfg.Security)
+	}
+	return nil
+}
+
+// genWiFiCredential generates the passphrase or WEP key and join payload
+func genWiFiCredential(cfg WiFiConfig) (*WiFiCredential, error) {
+	cred := &WiFiCredential{SSID: cfg.SSID, Security: cfg.Security}
+
+	qrType := "WPA"
+	switch cfg.Security {
+	case "wep":
+		n := 5 // 40-bit key for 64-bit WEP
+		if cfg.Bits == 128 {
+			n = 13
+		}
+		key, err := genWEPHexBytesWithError(n)
+		if err != nil {
+			return nil, err
+		}
+		cred.WEPKey = key
+		qrType = "WEP"
+	default:
+		var err error
+		if cfg.Style == "easy" {
+			cred.Passphrase, err = genEasyWithError(cfg.Length)
+		} else {
+			cred.Passphrase, err = genStrongWithError(cfg.Length)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Security == "wpa2" {
+			if cred.PSK, err = wpaPSK(cred.Passphrase, cfg.SSID); err != nil {
+				return nil, err
+			}
+		} else {
+			// WPA3-only networks are advertised as SAE in join codes
+			qrType = "SAE"
+		}
+	}
+
+	secret := cred.Passphrase
+	if secret == "" {
+		secret = cred.WEPKey
+	}
+	cred.QRPayload = wifiQRPayload(qrType, cfg.SSID, secret, cfg.Hidden)
+	return cred, nil
+}
+
+// wpaPSK derives the IEEE 802.11i pre-shared key from a passphrase and SSID
+func wpaPSK(passphrase, ssid string) (string, error) {
+	key, err := pbkdf2.Key(sha1.New, passphrase, []byte(ssid), 4096, 32)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive PSK: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// wifiQRPayload builds the WIFI: join string understood by phone cameras
+func wifiQRPayload(securityType, ssid, secret string, hidden bool) string {
+	payload := fmt.Sprintf("WIFI:T:%s;S:%s;P:%s;", securityType, wifiEscape(ssid), wifiEscape(secret))
+	if hidden {
+		payload += "H:true;"
+	}
+	return payload + ";"
+}
+
+// wifiEscape backslash-escapes the characters reserved by the WIFI: format
+func wifiEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`\;,:"`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// wifiCredentialItems lists the credential's secrets for --output
+func wifiCredentialItems(cred *WiFiCredential, cfg WiFiConfig) []SecretItem {
+	if cred.WEPKey != "" {
+		return []SecretItem{newSecretItem("wep_key", cred.WEPKey, float64(len(cred.WEPKey)*4))}
+	}
+	bits := strongEntropyBits(cfg.Length)
+	if cfg.Style == "easy" {
+		bits = easyEntropyBits(cfg.Length)
+	}
+	items := []SecretItem{newSecretItem("wifi_passphrase", cred.Passphrase, bits)}
+	if cred.PSK != "" {
+		items = append(items, newSecretItem("wifi_psk", cred.PSK, bits))
+	}
+	return items
+}
+
+// printWiFiCredential outputs the credential in the requested format
+func printWiFiCredential(w io.Writer, cred *WiFiCredential, cfg WiFiConfig) error {
+	if cfg.Output.structured() {
+		return printSecretItems(w, wifiCredentialItems(cred, cfg), cfg.Output)
+	}
+	if cfg.AsJSON {
+		return printJSON(w, cred, "Wi-Fi credential")
+	}
+
+	fmt.Fprintf(w, "SSID:       %s\n", cred.SSID)
+	fmt.Fprintf(w, "Security:   %s\n", strings.ToUpper(cred.Security))
+	if cred.Passphrase != "" {
+		fmt.Fprintf(w, "Passphrase: %s\n", cred.Passphrase)
+	}
+	if cred.PSK != "" {
+		fmt.Fprintf(w, "PSK:        %s\n", cred.PSK)
+	}
+	if cred.WEPKey != "" {
+		fmt.Fprintf(w, "WEP key:    %s\n", cred.WEPKey)
+	}
+	if cred.QRPNG != "" {
+		fmt.Fprintf(w, "QR:         %s\n", cred.QRPNG)
+	}
+	if cfg.QR {
+		qr, err := renderQRTerminal(cred.QRPayload)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+		fmt.Fprint(w, qr)
+	}
+	return nil
+}
+
+func init() {
+	createCmd.AddCommand(createWiFiCmd)
+
+	createWiFiCmd.Flags().String("ssid", "", "network name (required)")
+	createWiFiCmd.Flags().String("security", "wpa2", "security mode (wpa2|wpa3|wep)")
+	createWiFiCmd.Flags().String("style", "strong", "passphrase style (strong|easy)")
+	createWiFiCmd.Flags().IntP("length", "l", 20, "passphrase length (8-63)")
+	createWiFiCmd.Flags().IntP("bits", "b", 128, "WEP key size (64|128)")
+	createWiFiCmd.Flags().Bool("hidden", false, "mark the network as hidden in the QR payload")
+	createWiFiCmd.Flags().Bool("qr", false, "render the join QR code in the terminal")
+	createWiFiCmd.Flags().String("qr-png", "", "write the join QR code PNG to this file")
+	createWiFiCmd.Flags().Bool("json", false, "output as JSON")
+	addOutputFlags(createWiFiCmd)
+}
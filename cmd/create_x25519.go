@@ -0,0 +1,196 @@
+// cmd/create_x25519.go
+package cmd
+
+import (
+	"crypto/ecdh"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// WireGuardKeys is a WireGuard peer keypair with an optional preshared key
+type WireGuardKeys struct {
+	PrivateKey   string `json:"private_key"`
+	PublicKey    string `json:"public_key"`
+	PresharedKey string `json:"preshared_key,omitempty"`
+}
+
+// AgeKeys is an age X25519 identity and its recipient
+type AgeKeys struct {
+	Identity  string `json:"identity"`
+	Recipient string `json:"recipient"`
+	Created   string `json:"created"`
+}
+
+var createWireGuardCmd = &cobra.Command{
+	Use:   "wireguard",
+	Short: "Create a WireGuard keypair and preshared key (base64)",
+	Long: `Generate Curve25519 WireGuard keys in the base64 format used by wg(8):
+a clamped private key, its public key and a 256-bit preshared key.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		w := cmd.OutOrStdout()
+		count, _ := cmd.Flags().GetInt("count")
+		psk, _ := cmd.Flags().GetBool("psk")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		peers := make([]WireGuardKeys, 0, count)
+		for i := 0; i < count; i++ {
+			keys, err := genWireGuardKeys(psk)
+			if err != nil {
+				return fmt.Errorf("failed to generate WireGuard keys: %w", err)
+			}
+			peers = append(peers, *keys)
+		}
+
+		if out := getOutputConfigFromFlags(cmd); out.structured() {
+			var items []SecretItem
+			for _, p := range peers {
+				items = append(items,
+					newSecretItem("wireguard_private_key", p.PrivateKey, x25519KeyBits),
+					newSecretItem("wireguard_public_key", p.PublicKey, 0))
+				if p.PresharedKey != "" {
+					items = append(items, newSecretItem("wireguard_preshared_key", p.PresharedKey, 256))
+				}
+			}
+			return printSecretItems(w, items, out)
+		}
+		if asJSON {
+			return printJSON(w, peers, "WireGuard keys")
+		}
+		for i, p := range peers {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "PrivateKey = %s\n", p.PrivateKey)
+			fmt.Fprintf(w, "PublicKey = %s\n", p.PublicKey)
+			if p.PresharedKey != "" {
+				fmt.Fprintf(w, "PresharedKey = %s\n", p.PresharedKey)
+			}
+		}
+		return nil
+	},
+}
+
+var createAgeCmd = &cobra.Command{
+	Use:   "age",
+	Short: "Create an age X25519 identity and recipient",
+	Long: `Generate an age X25519 identity (AGE-SECRET-KEY-1...) and its recipient
+(age1...) in age's Bech32 format. Plain output matches age-keygen and can be
+saved directly as an identity file; --out writes it with 0600 permissions.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		w := cmd.OutOrStdout()
+		out, _ := cmd.Flags().GetString("out")
+		force, _ := cmd.Flags().GetBool("force")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		keys, err := genAgeKeys()
+		if err != nil {
+			return fmt.Errorf("failed to generate age identity: %w", err)
+		}
+
+		identityFile := fmt.Sprintf("# created: %s\n# public key: %s\n%s\n", keys.Created, keys.Recipient, keys.Identity)
+		if out != "" {
+			if err := writeSecretFile(out, []byte(identityFile), 0o600, force); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "Public key: %s\n", keys.Recipient)
+			return nil
+		}
+		if o := getOutputConfigFromFlags(cmd); o.structured() {
+			return printSecretItems(w, []SecretItem{
+				newSecretItem("age_identity", keys.Identity, x25519KeyBits),
+				newSecretItem("age_recipient", keys.Recipient, 0),
+			}, o)
+		}
+		if asJSON {
+			return printJSON(w, keys, "age identity")
+		}
+		fmt.Fprint(w, identityFile)
+		return nil
+	},
+}
+
+// x25519KeyBits is the entropy left in a private key after clamping
+const x25519KeyBits = 251
+
+// genX25519Key returns a fresh X25519 private key from crypto/rand
+func genX25519Key() (*ecdh.PrivateKey, error) {
+	b, err := genRandomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	// Clamp as wg(8) does so the stored key is already a valid scalar
+	b[0] &= 248
+	b[31] = (b[31] & 127) | 64
+	return ecdh.X25519().NewPrivateKey(b)
+}
+
+// genWireGuardKeys generates a WireGuard keypair and optional preshared key
+func genWireGuardKeys(withPSK bool) (*WireGuardKeys, error) {
+	priv, err := genX25519Key()
+	if err != nil {
+		return nil, err
+	}
+	keys := &WireGuardKeys{
+		PrivateKey: base64.StdEncoding.EncodeToString(priv.Bytes()),
+		PublicKey:  base64.StdEncoding.EncodeToString(priv.PublicKey().Bytes()),
+	}
+	if withPSK {
+		psk, err := genRandomBytes(32)
+		if err != nil {
+			return nil, err
+		}
+		keys.PresharedKey = base64.StdEncoding.EncodeToString(psk)
+	}
+	return keys, nil
+}
+
+// genAgeKeys generates an age X25519 identity and recipient
+func genAgeKeys() (*AgeKeys, error) {
+	priv, err := genX25519Key()
+	if err != nil {
+		return nil, err
+	}
+	identity, err := bech32Encode("age-secret-key-", priv.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode identity: %w", err)
+	}
+	recipient, err := bech32Encode("age", priv.PublicKey().Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode recipient: %w", err)
+	}
+	return &AgeKeys{
+		Identity:  strings.ToUpper(identity),
+		Recipient: recipient,
+		Created:   time.Now().Format(time.RFC3339),
+	}, nil
+}
+
+// printJSON outputs v as indented JSON
+func printJSON(w io.Writer, v any, what string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s to JSON: %w", what, err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+func init() {
+	createCmd.AddCommand(createWireGuardCmd)
+	createCmd.AddCommand(createAgeCmd)
+
+	createWireGuardCmd.Flags().IntP("count", "c", 1, "number of peer keypairs to generate")
+	createWireGuardCmd.Flags().Bool("psk", true, "include a preshared key for each peer")
+	createWireGuardCmd.Flags().Bool("json", false, "output as JSON array")
+	addOutputFlags(createWireGuardCmd)
+
+	createAgeCmd.Flags().StringP("out", "o", "", "write the identity file here with 0600 permissions")
+	createAgeCmd.Flags().Bool("json", false, "output as JSON")
+	addOutputFlags(createAgeCmd)
+}
@@ -0,0 +1,240 @@
+// cmd/detectors.go
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Default entropy thresholds (bits per character) for generic detection
+const (
+	defaultMinEntropy    = 4.0
+	assignmentMinEntropy = 3.0
+	// 99% of random 32-character hex strings score above 3.27 bits/char
+	hexMinEntropy = 3.2
+	// Random base62 changes character class between ~62% of adjacent pairs;
+	// identifiers and paths change far less often
+	minClassTransitions = 0.35
+	// allowMarker on a line suppresses findings on that line
+	allowMarker = "keyforge:allow"
+)
+
+// secretDetector finds one kind of secret in a line of text
+type secretDetector struct {
+	ID          string
+	Description string
+	Re          *regexp.Regexp
+	// Group selects the capture group holding the secret (0 for the whole match)
+	Group int
+	// Valid, when set, must accept the candidate for it to be reported
+	Valid func(string) bool
+}
+
+// secretDetectors run in order; the generic high-entropy detector runs last
+// and skips spans already claimed by a specific one
+var secretDetectors = []secretDetector{
+	{
+		ID:          "private-key",
+		Description: "Private key block",
+		Re:          regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`),
+	},
+	{
+		ID:          "aws-access-key-id",
+		Description: "AWS access key ID",
+		Re:          regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`),
+	},
+	{
+		ID:          "aws-secret-access-key",
+		Description: "AWS secret access key",
+		Re:          regexp.MustCompile(`(?i)aws.{0,20}(?:secret|private).{0,20}['"\x60]([0-9a-zA-Z/+]{40})['"\x60]`),
+		Group:       1,
+	},
+	{
+		ID:          "jwt",
+		Description: "JSON Web Token",
+		Re:          regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`),
+	},
+	{
+		ID:          "github-token",
+		Description: "GitHub token",
+		Re:          regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})\b`),
+	},
+	{
+		ID:          "slack-token",
+		Description: "Slack token",
+		Re:          regexp.MustCompile(`\bxox[baprs]-[A-Za-z0-9-]{10,}`),
+	},
+	{
+		ID:          "stripe-key",
+		Description: "Stripe secret or restricted key",
+		Re:          regexp.MustCompile(`\b[sr]k_(?:live|test)_[A-Za-z0-9]{24,}\b`),
+	},
+	{
+		ID:          "google-api-key",
+		Description: "Google API key",
+		Re:          regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`),
+	},
+	{
+		ID:          "age-secret-key",
+		Description: "age identity",
+		Re:          regexp.MustCompile(`\bAGE-SECRET-KEY-1[0-9A-Z]{58}\b`),
+	},
+	{
+		ID:          "keyforge-token",
+		Description: "keyforge API token (checksum verified)",
+		Re:          regexp.MustCompile(`\b[0-9A-Za-z_]*_[0-9A-Za-z]{20,}\b`),
+		Valid:       func(s string) bool { return verifyToken(s, "") == nil },
+	},
+	{
+		ID:          "password-assignment",
+		Description: "Credential assigned in code or config",
+		Re:          regexp.MustCompile(`(?i)(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key)\w*['"]?\s*[:=]\s*['"\x60]([^'"\x60\s]{8,})['"\x60]`),
+		Group:       1,
+		Valid: func(s string) bool {
+			for _, p := range []string{"${", "{{", "<", "%(", "$("} {
+				if strings.HasPrefix(s, p) {
+					return false
+				}
+			}
+			return shannonEntropy(s) >= assignmentMinEntropy
+		},
+	},
+}
+
+// genericSecretRe matches base64/base62-like words long enough to be keys;
+// hexSecretRe matches hex keys of 128 bits or more
+var (
+	genericSecretRe = regexp.MustCompile(`[A-Za-z0-9+/_=-]{20,}`)
+	hexSecretRe     = regexp.MustCompile(`\b[0-9a-fA-F]{32,}\b`)
+)
+
+// secretMatch is a detector hit within a single line
+type secretMatch struct {
+	Rule        string
+	Description string
+	Column      int
+	Secret      string
+	Entropy     float64
+}
+
+// findSecrets runs all detectors over line. Generic matches need at least
+// minEntropy bits per character and lower, upper and digit characters.
+func findSecrets(line string, minEntropy float64) []secretMatch {
+	if strings.Contains(line, allowMarker) {
+		return nil
+	}
+
+	var out []secretMatch
+	var claimed [][2]int
+	for _, d := range secretDetectors {
+		for _, loc := range d.Re.FindAllStringSubmatchIndex(line, -1) {
+			start, end := loc[2*d.Group], loc[2*d.Group+1]
+			if start < 0 {
+				continue
+			}
+			s := line[start:end]
+			if d.Valid != nil && !d.Valid(s) {
+				continue
+			}
+			if overlaps(claimed, start, end) {
+				continue
+			}
+			claimed = append(claimed, [2]int{start, end})
+			out = append(out, secretMatch{Rule: d.ID, Description: d.Description, Column: start + 1, Secret: s, Entropy: shannonEntropy(s)})
+		}
+	}
+
+	for _, loc := range genericSecretRe.FindAllStringIndex(line, -1) {
+		s := line[loc[0]:loc[1]]
+		if overlaps(claimed, loc[0], loc[1]) || !looksRandom(s) {
+			continue
+		}
+		if e := shannonEntropy(s); e >= minEntropy {
+			claimed = append(claimed, [2]int{loc[0], loc[1]})
+			out = append(out, secretMatch{Rule: "high-entropy", Description: "High-entropy string", Column: loc[0] + 1, Secret: s, Entropy: e})
+		}
+	}
+	for _, loc := range hexSecretRe.FindAllStringIndex(line, -1) {
+		s := line[loc[0]:loc[1]]
+		// Random keys often hold a run of four, so only the hex alphabet
+		// literal itself is filtered here
+		if overlaps(claimed, loc[0], loc[1]) || hasSequentialRun(s, 10) {
+			continue
+		}
+		if e := shannonEntropy(s); e >= hexMinEntropy {
+			out = append(out, secretMatch{Rule: "high-entropy", Description: "High-entropy hex string", Column: loc[0] + 1, Secret: s, Entropy: e})
+		}
+	}
+	return out
+}
+
+// looksRandom filters generic candidates that are identifiers, paths,
+// alphabet literals or SSH public key blobs
+func looksRandom(s string) bool {
+	if strings.HasPrefix(s, "AAAA") || charClasses(strings.Trim(s, "+/_=-")) < 3 || hasSequentialRun(s, 4) {
+		return false
+	}
+	transitions := 0
+	for i := 1; i < len(s); i++ {
+		if charClass(s[i]) != charClass(s[i-1]) {
+			transitions++
+		}
+	}
+	return float64(transitions)/float64(len(s)-1) >= minClassTransitions
+}
+
+// hasSequentialRun reports an ascending run of at least n characters such as
+// "abcd" or "0123", typical of alphabet constants rather than secrets
+func hasSequentialRun(s string, n int) bool {
+	run := 1
+	for i := 1; i < len(s); i++ {
+		if s[i] == s[i-1]+1 {
+			if run++; run >= n {
+				return true
+			}
+		} else {
+			run = 1
+		}
+	}
+	return false
+}
+
+func charClass(c byte) int {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return 0
+	case c >= 'A' && c <= 'Z':
+		return 1
+	case c >= '0' && c <= '9':
+		return 2
+	default:
+		return 3
+	}
+}
+
+func overlaps(spans [][2]int, start, end int) bool {
+	for _, sp := range spans {
+		if start < sp[1] && sp[0] < end {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecret keeps the first four characters so a finding can be
+// recognized without reproducing the secret
+func redactSecret(s string) string {
+	if len(s) <= 8 {
+		return "********"
+	}
+	return s[:4] + "********"
+}
+
+// secretFingerprint identifies a secret in reports and baselines without
+// revealing it
+func secretFingerprint(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,68 @@
+// cmd/encoding.go
+package cmd
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	base58Alphabet    = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// keyEncodings lists the encodings accepted by encodeBytes
+var keyEncodings = []string{"hex", "base64", "base64url", "base32", "base58", "crockford", "raw"}
+
+var crockfordEncoding = base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding)
+
+// encodeBytes renders b in the named encoding
+func encodeBytes(b []byte, encoding string) (string, error) {
+	switch encoding {
+	case "hex":
+		return hex.EncodeToString(b), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(b), nil
+	case "base64url":
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	case "base32":
+		return base32.StdEncoding.EncodeToString(b), nil
+	case "base58":
+		return encodeBaseN(b, base58Alphabet), nil
+	case "crockford":
+		return crockfordEncoding.EncodeToString(b), nil
+	case "raw":
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("unsupported encoding %q (want one of: %s)", encoding, strings.Join(keyEncodings, ", "))
+	}
+}
+
+// encodeBaseN encodes b as a big-endian number in the given alphabet,
+// keeping leading zero bytes as leading zero digits (Bitcoin base58 style)
+func encodeBaseN(b []byte, alphabet string) string {
+	base := big.NewInt(int64(len(alphabet)))
+	n := new(big.Int).SetBytes(b)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
@@ -0,0 +1,200 @@
+// cmd/encrypt.go
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	ageArmor "filippo.io/age/armor"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/spf13/cobra"
+)
+
+// Recipients are the public keys output is encrypted to, either age X25519
+// recipients or OpenPGP keys; one message cannot be readable by both
+type Recipients struct {
+	Age []age.Recipient
+	PGP openpgp.EntityList
+}
+
+// secretRecipients is set while a command runs with --encrypt-to or
+// --recipient-file, so private files are encrypted as they are written
+var secretRecipients *Recipients
+
+// withEncryption wraps a create command so that everything it writes to
+// cmd.OutOrStdout() is captured in memory and encrypted before it is written
+func withEncryption(run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		r, err := getRecipientsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			return run(cmd, args)
+		}
+		secretRecipients = r
+		defer func() { secretRecipients = nil }()
+
+		var plain bytes.Buffer
+		stdout := cmd.OutOrStdout()
+		restore := redirectOut(cmd, &plain)
+		err = run(cmd, args)
+		restore()
+		defer clear(plain.Bytes())
+		if err != nil {
+			return err
+		}
+		if plain.Len() == 0 {
+			return nil
+		}
+		ciphertext, err := r.Encrypt(plain.Bytes())
+		if err != nil {
+			return err
+		}
+		_, err = stdout.Write(ciphertext)
+		return err
+	}
+}
+
+// getRecipientsFromFlags loads --encrypt-to and --recipient-file, returning
+// nil when neither is set
+func getRecipientsFromFlags(cmd *cobra.Command) (*Recipients, error) {
+	encryptTo, _ := cmd.Flags().GetStringArray("encrypt-to")
+	files, _ := cmd.Flags().GetStringArray("recipient-file")
+	if len(encryptTo) == 0 && len(files) == 0 {
+		return nil, nil
+	}
+
+	r := &Recipients{}
+	for _, s := range encryptTo {
+		pub, err := parseAgeRecipient(s)
+		if err != nil {
+			return nil, err
+		}
+		r.Age = append(r.Age, pub)
+	}
+	for _, name := range files {
+		if err := r.addFile(name); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.Age) > 0 && len(r.PGP) > 0 {
+		return nil, fmt.Errorf("cannot mix age and OpenPGP recipients")
+	}
+	if len(r.Age) == 0 && len(r.PGP) == 0 {
+		return nil, fmt.Errorf("no recipients found")
+	}
+	return r, nil
+}
+
+// addFile reads an OpenPGP public key (armored or binary) or a list of age
+// recipients, one per line with # comments, as accepted by age -R
+func (r *Recipients) addFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("failed to read recipient file: %w", err)
+	}
+
+	if bytes.Contains(data, []byte("-----BEGIN PGP PUBLIC KEY BLOCK-----")) || (len(data) > 0 && data[0]&0x80 != 0) {
+		var keys openpgp.EntityList
+		if data[0]&0x80 != 0 {
+			keys, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+		} else {
+			keys, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		}
+		if err != nil {
+			return fmt.Errorf("%s: failed to read OpenPGP key: %w", name, err)
+		}
+		for _, e := range keys {
+			if _, ok := e.EncryptionKey(time.Now()); !ok {
+				return fmt.Errorf("%s: OpenPGP key %s has no usable encryption key", name, e.PrimaryKey.KeyIdString())
+			}
+		}
+		r.PGP = append(r.PGP, keys...)
+		return nil
+	}
+
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pub, err := parseAgeRecipient(line)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", name, n+1, err)
+		}
+		r.Age = append(r.Age, pub)
+	}
+	return nil
+}
+
+// parseAgeRecipient decodes an age1... X25519 recipient. An identity is
+// rejected without echoing any of it.
+func parseAgeRecipient(s string) (age.Recipient, error) {
+	if strings.HasPrefix(strings.ToUpper(s), "AGE-SECRET-KEY-") {
+		return nil, fmt.Errorf("argument is an age identity (secret key); pass its public key (age1...) instead")
+	}
+	r, err := age.ParseX25519Recipient(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age recipient: %w", err)
+	}
+	return r, nil
+}
+
+// Encrypt returns plaintext encrypted to the recipients, ASCII-armored so
+// it is safe to print to a terminal
+func (r *Recipients) Encrypt(plaintext []byte) ([]byte, error) {
+	if len(r.PGP) > 0 {
+		return encryptPGP(plaintext, r.PGP)
+	}
+	return encryptAge(plaintext, r.Age)
+}
+
+// encryptPGP encrypts plaintext to OpenPGP keys as an armored message
+func encryptPGP(plaintext []byte, to openpgp.EntityList) ([]byte, error) {
+	var b bytes.Buffer
+	aw, err := armor.Encode(&b, "PGP MESSAGE", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	w, err := openpgp.Encrypt(aw, to, nil, &openpgp.FileHints{IsBinary: true}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	if err := aw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// encryptAge encrypts plaintext to age recipients as an armored age file
+func encryptAge(plaintext []byte, to []age.Recipient) ([]byte, error) {
+	var b bytes.Buffer
+	aw := ageArmor.NewWriter(&b)
+	w, err := age.Encrypt(aw, to...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	if err := aw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt output: %w", err)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,347 @@
+// cmd/fill.go
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// generatorKinds lists the generators usable in placeholders
+var generatorKinds = []string{"strong", "easy", "key", "token", "mnemonic", "64wep", "128wep", "256wep"}
+
+var (
+	// fillPlaceholderRe matches ${keyforge:<spec>} and <generate:<spec>>
+	fillPlaceholderRe = regexp.MustCompile(`\$\{keyforge:([^}]+)\}|<generate:([^>]+)>`)
+	// envAssignRe splits a dotenv line into prefix, key, separator and value
+	envAssignRe = regexp.MustCompile(`^(\s*(?:export\s+)?)([A-Za-z_][A-Za-z0-9_.]*)(\s*=\s*)(.*)$`)
+)
+
+var fillCmd = &cobra.Command{
+	Use:   "fill [template]",
+	Short: "Fill generator placeholders in a .env template",
+	Long: `Copy a dotenv template (such as .env.example), replacing each placeholder
+in a variable's value with a freshly generated secret:
+
+  DB_PASSWORD=${keyforge:strong:32}
+  SECRET_KEY=<generate:key:256:base64>
+  API_TOKEN=${keyforge:token:32:acme_}
+
+A placeholder is a generator and its arguments separated by colons:
+  strong[:length]  easy[:length]  key[:bits[:encoding]]
+  token[:bytes[:prefix]]  mnemonic[:words]  64wep  128wep  256wep
+
+A value that is only a placeholder is quoted as needed. A placeholder
+embedded in a longer value is escaped for its surroundings: backslash
+escapes inside double quotes, percent-encoding when unquoted (so
+DATABASE_URL=postgres://app:${keyforge:strong}@db/app stays a valid URL)
+and nothing inside single quotes.
+
+Comments, blank lines and values without placeholders are copied unchanged.
+With --existing, variables that already have a non-empty value in that file
+(usually the .env being regenerated) keep it instead of getting a new secret.
+Use --out rather than shell redirection to write the result with 0600
+permissions.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := cmd.Flags().GetString("out")
+		force, _ := cmd.Flags().GetBool("force")
+		existingPath, _ := cmd.Flags().GetString("existing")
+
+		tmpl, err := readFillInput(args[0])
+		if err != nil {
+			return err
+		}
+		existing := map[string]string{}
+		if existingPath != "" {
+			if existing, err = readEnvValues(existingPath); err != nil {
+				return err
+			}
+		}
+		if out != "" {
+			if err := checkOverwrite(out, force); err != nil {
+				return err
+			}
+		}
+
+		filled, generated, kept, err := fillTemplate(tmpl, existing)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Generated %d values, kept %d existing\n", generated, kept)
+		if out != "" {
+			return writeSecretFile(out, filled, 0o600, force)
+		}
+		_, err = os.Stdout.Write(filled)
+		return err
+	},
+}
+
+// readFillInput reads the template from a file or "-" for STDIN
+func readFillInput(name string) ([]byte, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	return data, nil
+}
+
+// readEnvValues returns the raw, non-empty values assigned in a dotenv file.
+// A missing file is treated as empty so the first run needs no special case.
+func readEnvValues(name string) (map[string]string, error) {
+	values := map[string]string{}
+	data, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read existing values: %w", err)
+	}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		m := envAssignRe.FindStringSubmatch(s.Text())
+		if m == nil {
+			continue
+		}
+		if v := strings.TrimSpace(m[4]); v != "" && v != `""` && v != "''" {
+			values[m[2]] = v
+		}
+	}
+	return values, s.Err()
+}
+
+// fillTemplate replaces the placeholders in every assignment, returning the
+// result and how many values were generated and kept
+func fillTemplate(tmpl []byte, existing map[string]string) ([]byte, int, int, error) {
+	var b bytes.Buffer
+	generated, kept := 0, 0
+	s := bufio.NewScanner(bytes.NewReader(tmpl))
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		m := envAssignRe.FindStringSubmatch(line)
+		if m == nil || !fillPlaceholderRe.MatchString(m[4]) {
+			b.WriteString(line + "\n")
+			continue
+		}
+		prefix, key, sep, value := m[1], m[2], m[3], m[4]
+
+		if v, ok := existing[key]; ok {
+			b.WriteString(prefix + key + sep + v + "\n")
+			kept++
+			continue
+		}
+
+		// A value that is only a placeholder is re-quoted for the generated
+		// secret; placeholders embedded in a longer value are escaped for
+		// the quotes around them. A trailing comment is set aside first so
+		// it does not make a whole placeholder look embedded.
+		value, comment := splitEnvComment(value)
+		trimmed := strings.Trim(strings.TrimSpace(value), `"'`)
+		whole := fillPlaceholderRe.FindString(trimmed) == trimmed
+		var filled strings.Builder
+		last := 0
+		for _, loc := range fillPlaceholderRe.FindAllStringSubmatchIndex(value, -1) {
+			filled.WriteString(value[last:loc[0]])
+			last = loc[1]
+			var spec string
+			if loc[2] >= 0 {
+				spec = value[loc[2]:loc[3]]
+			} else {
+				spec = value[loc[4]:loc[5]]
+			}
+			v, _, err := generateFromSpec(spec)
+			if err == nil && !whole {
+				v, err = embedValue(v, quoteContext(value[:loc[0]]))
+			}
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("line %d: %s: %w", n, key, err)
+			}
+			filled.WriteString(v)
+			generated++
+		}
+		filled.WriteString(value[last:])
+		value = filled.String()
+		if whole {
+			value = envQuote(strings.Trim(strings.TrimSpace(value), `"'`))
+		}
+		b.WriteString(prefix + key + sep + value + comment + "\n")
+	}
+	if err := s.Err(); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to read template: %w", err)
+	}
+	return b.Bytes(), generated, kept, nil
+}
+
+// splitEnvComment splits an unquoted trailing " # comment" off a dotenv
+// value, returning the comment with the whitespace before it
+func splitEnvComment(value string) (string, string) {
+	var q byte
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case q == '"' && c == '\\':
+			i++
+		case q == 0 && (c == '"' || c == '\''):
+			q = c
+		case c == q:
+			q = 0
+		case q == 0 && c == '#' && i > 0 && (value[i-1] == ' ' || value[i-1] == '\t'):
+			j := len(strings.TrimRight(value[:i], " \t"))
+			return value[:j], value[j:]
+		}
+	}
+	return value, ""
+}
+
+// quoteContext returns the quote character open at the end of s, or 0 when
+// s ends outside quotes
+func quoteContext(s string) byte {
+	var q byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case q == '"' && c == '\\':
+			i++
+		case q == 0 && (c == '"' || c == '\''):
+			q = c
+		case c == q:
+			q = 0
+		}
+	}
+	return q
+}
+
+// embedValue escapes a generated value for the quotes it is embedded in:
+// backslash escapes inside double quotes and percent-encoding when unquoted,
+// which keeps it intact in a URL and away from dotenv comments. Inside single
+// quotes nothing can be escaped; no generator produces a quote character.
+func embedValue(v string, quote byte) (string, error) {
+	switch quote {
+	case '"':
+		return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`).Replace(v), nil
+	case '\'':
+		if strings.ContainsRune(v, '\'') {
+			return "", fmt.Errorf("generated value contains a single quote and cannot be embedded in single quotes")
+		}
+		return v, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String(), nil
+}
+
+// generateFromSpec generates one value from a generator spec such as
+// "strong:32" or "key:256:base64", returning it with its entropy in bits
+func generateFromSpec(spec string) (string, float64, error) {
+	args := strings.Split(strings.TrimSpace(spec), ":")
+	kind := strings.ToLower(args[0])
+	intArg := func(i, def int) (int, error) {
+		if len(args) <= i || args[i] == "" {
+			return def, nil
+		}
+		v, err := strconv.Atoi(args[i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q in %q", args[i], spec)
+		}
+		return v, nil
+	}
+	strArg := func(i int, def string) string {
+		if len(args) <= i || args[i] == "" {
+			return def
+		}
+		return args[i]
+	}
+
+	switch kind {
+	case "strong", "easy":
+		def := 20
+		if kind == "easy" {
+			def = 12
+		}
+		n, err := intArg(1, def)
+		if err != nil {
+			return "", 0, err
+		}
+		minLength := minStrongLength
+		if kind == "easy" {
+			minLength = minEasyLength
+		}
+		if n < minLength || n > maxPasswordLength {
+			return "", 0, fmt.Errorf("%s length must be %d-%d, got: %d", kind, minLength, maxPasswordLength, n)
+		}
+		if kind == "easy" {
+			v, err := genEasyWithError(n)
+			return v, easyEntropyBits(n), err
+		}
+		v, err := genStrongWithError(n)
+		return v, strongEntropyBits(n), err
+	case "key":
+		bits, err := intArg(1, 256)
+		if err != nil {
+			return "", 0, err
+		}
+		encoding := strings.ToLower(strArg(2, "hex"))
+		if encoding == "raw" {
+			return "", 0, fmt.Errorf("raw encoding cannot be used in a text value")
+		}
+		if err := validateKeyConfig(KeyConfig{Config: Config{Count: 1}, Bits: bits, Encoding: encoding}); err != nil {
+			return "", 0, err
+		}
+		v, err := genKeyWithError(bits, encoding)
+		return v, float64(bits), err
+	case "token":
+		n, err := intArg(1, 32)
+		if err != nil {
+			return "", 0, err
+		}
+		if n < 16 {
+			return "", 0, fmt.Errorf("bytes must be at least 16, got: %d", n)
+		}
+		prefix := strArg(2, "kf_")
+		if strings.IndexFunc(prefix, func(r rune) bool { return !isTokenPrefixRune(r) }) >= 0 {
+			return "", 0, fmt.Errorf("prefix may only contain letters, digits and underscores: %q", prefix)
+		}
+		bodyLen := tokenBodyLen(n)
+		v, err := genTokenWithError(prefix, bodyLen, true)
+		return v, float64(bodyLen) * math.Log2(float64(len(base62Alphabet))), err
+	case "mnemonic":
+		words, err := intArg(1, 24)
+		if err != nil {
+			return "", 0, err
+		}
+		v, err := genMnemonic(words, "english")
+		return v, float64(words / 3 * 32), err
+	case "64wep", "128wep", "256wep":
+		n := map[string]int{"64wep": 5, "128wep": 13, "256wep": 29}[kind]
+		v, err := genWEPHexBytesWithError(n)
+		return v, float64(n * 8), err
+	}
+	return "", 0, fmt.Errorf("unknown generator %q (want one of: %s)", kind, strings.Join(generatorKinds, ", "))
+}
+
+func init() {
+	rootCmd.AddCommand(fillCmd)
+
+	fillCmd.Flags().StringP("out", "o", "", "write the filled file here atomically with 0600 permissions")
+	fillCmd.Flags().Bool("force", false, "overwrite --out if it exists")
+	fillCmd.Flags().String("existing", "", "keep non-empty values already set in this dotenv file")
+}
@@ -0,0 +1,58 @@
+// cmd/fill_test.go
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFillTemplatePlaceholders(t *testing.T) {
+	tmpl := "# settings\n" +
+		"A=${keyforge:key:128:hex}\n" +
+		"B=<generate:key:128:hex>\n" +
+		"C=${keyforge:key:128:hex}  # db password\n" +
+		"D=\"<generate:key:128:hex>\" # quoted\n" +
+		"E=postgres://app:<generate:strong:24>@db/app\n"
+
+	out, generated, kept, err := fillTemplate([]byte(tmpl), map[string]string{})
+	if err != nil {
+		t.Fatalf("fillTemplate: %v", err)
+	}
+	if generated != 5 || kept != 0 {
+		t.Fatalf("generated %d, kept %d; want 5, 0", generated, kept)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	want := []*regexp.Regexp{
+		regexp.MustCompile(`^# settings$`),
+		regexp.MustCompile(`^A=[0-9a-f]{32}$`),
+		regexp.MustCompile(`^B=[0-9a-f]{32}$`),
+		regexp.MustCompile(`^C=[0-9a-f]{32}  # db password$`),
+		regexp.MustCompile(`^D=[0-9a-f]{32} # quoted$`),
+		regexp.MustCompile(`^E=postgres://app:[0-9A-Za-z%._~-]+@db/app$`),
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), out)
+	}
+	for i, re := range want {
+		if !re.MatchString(lines[i]) {
+			t.Errorf("line %d = %q, want match for %s", i+1, lines[i], re)
+		}
+	}
+}
+
+func TestFillTemplateKeepsWholeValueBeforeComment(t *testing.T) {
+	out, _, _, err := fillTemplate([]byte("A=${keyforge:strong:24}  # db password\n"), nil)
+	if err != nil {
+		t.Fatalf("fillTemplate: %v", err)
+	}
+	line := strings.TrimSuffix(string(out), "\n")
+	if !strings.HasSuffix(line, "  # db password") {
+		t.Fatalf("comment lost: %q", line)
+	}
+	value := strings.TrimSuffix(strings.TrimPrefix(line, "A="), "  # db password")
+	if v := strings.Trim(value, "'"); len(v) != 24 {
+		t.Errorf("value %q is not the 24-character secret", value)
+	}
+}
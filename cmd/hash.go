@@ -0,0 +1,144 @@
+// cmd/hash.go
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// HashedResult pairs a generated password with its storage hash and/or
+// system credential
+type HashedResult struct {
+	Password   string `json:"password"`
+	User       string `json:"user,omitempty"`
+	Hash       string `json:"hash,omitempty"`
+	Credential string `json:"credential,omitempty"`
+}
+
+var hashCmd = &cobra.Command{
+	Use:   "hash",
+	Short: "Hash an existing password for storage",
+	Long: `Hash a password in a storage format: PHC strings for bcrypt, argon2id,
+scrypt and pbkdf2-sha256, or crypt(3) for sha512-crypt (/etc/shadow).
+
+--credential emits a system-specific value instead: an htpasswd line, an LDAP
+userPassword ({SSHA} or {CRYPT}), a PostgreSQL SCRAM-SHA-256 verifier, or a
+MySQL caching_sha2_password / mysql_native_password hash.
+
+The password is never taken as an argument. On a terminal it is prompted
+for twice without echo; otherwise the first line of STDIN is used.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		algo, _ := cmd.Flags().GetString("algo")
+		credential, _ := cmd.Flags().GetString("credential")
+		user, _ := cmd.Flags().GetString("user")
+		cfg := Config{Credential: strings.ToLower(credential), User: user}
+		if cfg.Credential == "" {
+			cfg.Hash = strings.ToLower(algo)
+		}
+		if err := validatePasswordOutput(cfg); err != nil {
+			return err
+		}
+
+		pwd, err := readPasswordSecurely()
+		if err != nil {
+			return err
+		}
+		if pwd == "" {
+			return fmt.Errorf("password cannot be empty")
+		}
+
+		hashed, err := hashResults([]string{pwd}, cfg)
+		if err != nil {
+			return err
+		}
+		fmt.Println(hashed[0].Hash + hashed[0].Credential)
+		return nil
+	},
+}
+
+// readPasswordSecurely prompts without echo on a terminal (with confirmation)
+// or reads the first line of piped input
+func readPasswordSecurely() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		s := bufio.NewScanner(os.Stdin)
+		if s.Scan() {
+			return strings.TrimRight(s.Text(), "\r\n"), nil
+		}
+		return "", s.Err()
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	first, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	fmt.Fprint(os.Stderr, "Confirm:  ")
+	second, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	if string(first) != string(second) {
+		return "", fmt.Errorf("passwords do not match")
+	}
+	return string(first), nil
+}
+
+// hashResults hashes and/or formats each password as set in cfg
+func hashResults(results []string, cfg Config) ([]HashedResult, error) {
+	out := make([]HashedResult, 0, len(results))
+	for _, r := range results {
+		hr := HashedResult{Password: r}
+		if cfg.Hash != "" {
+			h, err := hashPassword(cfg.Hash, r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash password: %w", err)
+			}
+			hr.Hash = h
+		}
+		if cfg.Credential != "" {
+			c, err := formatCredential(cfg.Credential, cfg.User, r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to format credential: %w", err)
+			}
+			hr.User, hr.Credential = cfg.User, c
+		}
+		out = append(out, hr)
+	}
+	return out, nil
+}
+
+// printHashedResults outputs password/hash/credential rows as tab-separated
+// lines or JSON
+func printHashedResults(w io.Writer, results []HashedResult, asJSON bool) error {
+	if asJSON {
+		return printJSON(w, results, "hashed passwords")
+	}
+	for _, r := range results {
+		row := []string{r.Password}
+		for _, v := range []string{r.Hash, r.Credential} {
+			if v != "" {
+				row = append(row, v)
+			}
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(hashCmd)
+
+	hashCmd.Flags().StringP("algo", "a", "argon2id", "hash algorithm ("+strings.Join(hashAlgorithms, "|")+")")
+	hashCmd.Flags().String("credential", "", "emit a system credential format instead ("+strings.Join(credentialFormats, "|")+")")
+	hashCmd.Flags().String("user", "", "account name for credential formats that include one (htpasswd)")
+}
@@ -0,0 +1,247 @@
+// cmd/mnemonic.go
+package cmd
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+//go:embed wordlists/bip39_english.txt
+var bip39English string
+
+// bip39Wordlists maps --lang values to their embedded 2048-word lists
+var bip39Wordlists = map[string]*string{
+	"english": &bip39English,
+}
+
+var (
+	bip39Once  sync.Once
+	bip39Words map[string][]string
+	bip39Index map[string]map[string]int
+)
+
+// bip39Wordlist returns the word list and reverse index for lang
+func bip39Wordlist(lang string) ([]string, map[string]int, error) {
+	bip39Once.Do(func() {
+		bip39Words = make(map[string][]string)
+		bip39Index = make(map[string]map[string]int)
+		for name, raw := range bip39Wordlists {
+			words := strings.Fields(*raw)
+			index := make(map[string]int, len(words))
+			for i, w := range words {
+				index[w] = i
+			}
+			bip39Words[name], bip39Index[name] = words, index
+		}
+	})
+
+	words, ok := bip39Words[lang]
+	if !ok || len(words) != 2048 {
+		return nil, nil, fmt.Errorf("unsupported language %q (want one of: %s)", lang, strings.Join(bip39Languages(), ", "))
+	}
+	return words, bip39Index[lang], nil
+}
+
+// bip39Languages lists the embedded word lists
+func bip39Languages() []string {
+	langs := make([]string, 0, len(bip39Wordlists))
+	for name := range bip39Wordlists {
+		langs = append(langs, name)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// MnemonicReport is the result of validating a BIP39 mnemonic
+type MnemonicReport struct {
+	Words         int      `json:"words"`
+	EntropyBits   int      `json:"entropy_bits"`
+	UnknownWords  []string `json:"unknown_words,omitempty"`
+	ValidLength   bool     `json:"valid_length"`
+	ValidChecksum bool     `json:"valid_checksum"`
+	Valid         bool     `json:"valid"`
+}
+
+var createMnemonicCmd = &cobra.Command{
+	Use:   "mnemonic",
+	Short: "Create a BIP39 mnemonic phrase",
+	Long: `Generate a BIP39 mnemonic from fresh entropy with the correct checksum,
+using an embedded word list. 12 words carry 128 bits of entropy, 24 words 256.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		w := cmd.OutOrStdout()
+		words, _ := cmd.Flags().GetInt("words")
+		lang, _ := cmd.Flags().GetString("lang")
+		count, _ := cmd.Flags().GetInt("count")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		results := make([]string, 0, count)
+		for i := 0; i < count; i++ {
+			m, err := genMnemonic(words, strings.ToLower(lang))
+			if err != nil {
+				return fmt.Errorf("failed to generate mnemonic: %w", err)
+			}
+			results = append(results, m)
+		}
+		if out := getOutputConfigFromFlags(cmd); out.structured() {
+			return printSecretItems(w, newSecretItems("mnemonic", results, float64(words/3*32)), out)
+		}
+		return printResults(w, results, asJSON)
+	},
+}
+
+var analyzeMnemonicCmd = &cobra.Command{
+	Use:   "mnemonic [words]",
+	Short: "Validate a BIP39 mnemonic's words and checksum",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		phrase, err := readSecretArg(cmd, args, "mnemonic")
+		if err != nil {
+			return err
+		}
+		lang, _ := cmd.Flags().GetString("lang")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		report, err := analyzeMnemonic(phrase, strings.ToLower(lang))
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			if err := printJSON(cmd.OutOrStdout(), report, "mnemonic report"); err != nil {
+				return err
+			}
+		} else {
+			printMnemonicReport(report)
+		}
+		if !report.Valid {
+			return fmt.Errorf("mnemonic is not valid BIP39")
+		}
+		return nil
+	},
+}
+
+// genMnemonic generates a BIP39 mnemonic with the given number of words
+func genMnemonic(words int, lang string) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", fmt.Errorf("words must be 12, 15, 18, 21 or 24, got: %d", words)
+	}
+	list, _, err := bip39Wordlist(lang)
+	if err != nil {
+		return "", err
+	}
+
+	// Each 3 words carry 32 bits of entropy and 1 bit of checksum
+	entropy, err := genRandomBytes(words / 3 * 4)
+	if err != nil {
+		return "", err
+	}
+	return mnemonicFromEntropy(entropy, list), nil
+}
+
+// mnemonicFromEntropy appends the SHA-256 checksum bits to entropy and
+// splits the result into 11-bit word indices
+func mnemonicFromEntropy(entropy []byte, list []string) string {
+	checksumBits := len(entropy) * 8 / 32
+	sum := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), sum[0])
+
+	n := (len(entropy)*8 + checksumBits) / 11
+	out := make([]string, n)
+	for i := 0; i < n; i++ {
+		idx := 0
+		for b := i * 11; b < (i+1)*11; b++ {
+			idx = idx<<1 | int(data[b/8]>>(7-uint(b%8))&1)
+		}
+		out[i] = list[idx]
+	}
+	return strings.Join(out, " ")
+}
+
+// analyzeMnemonic checks word count, word list membership and checksum
+func analyzeMnemonic(phrase, lang string) (*MnemonicReport, error) {
+	list, index, err := bip39Wordlist(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.Fields(strings.ToLower(phrase))
+	report := &MnemonicReport{
+		Words:       len(words),
+		ValidLength: len(words) >= 12 && len(words) <= 24 && len(words)%3 == 0,
+	}
+	for _, w := range words {
+		if _, ok := index[w]; !ok {
+			report.UnknownWords = append(report.UnknownWords, w)
+		}
+	}
+	if !report.ValidLength || len(report.UnknownWords) > 0 {
+		return report, nil
+	}
+
+	// Rebuild the entropy from the word indices and recompute the phrase
+	entropyBytes := len(words) / 3 * 4
+	report.EntropyBits = entropyBytes * 8
+	bits := make([]byte, len(words)*11)
+	for i, w := range words {
+		idx := index[w]
+		for b := 0; b < 11; b++ {
+			bits[i*11+b] = byte(idx>>(10-uint(b))) & 1
+		}
+	}
+	entropy := make([]byte, entropyBytes)
+	for i := range entropy {
+		for b := 0; b < 8; b++ {
+			entropy[i] = entropy[i]<<1 | bits[i*8+b]
+		}
+	}
+	report.ValidChecksum = mnemonicFromEntropy(entropy, list) == strings.Join(words, " ")
+	report.Valid = report.ValidChecksum
+	return report, nil
+}
+
+// printMnemonicReport outputs the validation result as text
+func printMnemonicReport(r *MnemonicReport) {
+	fmt.Printf("Words: %d\n", r.Words)
+	if r.EntropyBits > 0 {
+		fmt.Printf("Entropy: %d bits\n", r.EntropyBits)
+	}
+	if !r.ValidLength {
+		fmt.Println("Length: invalid (want 12, 15, 18, 21 or 24 words)")
+	}
+	if len(r.UnknownWords) > 0 {
+		fmt.Printf("Unknown words: %s\n", strings.Join(r.UnknownWords, ", "))
+	}
+	if r.ValidLength && len(r.UnknownWords) == 0 {
+		if r.ValidChecksum {
+			fmt.Println("Checksum: valid")
+		} else {
+			fmt.Println("Checksum: invalid")
+		}
+	}
+	if r.Valid {
+		fmt.Println("Verdict: Valid")
+	} else {
+		fmt.Println("Verdict: Invalid")
+	}
+}
+
+func init() {
+	createCmd.AddCommand(createMnemonicCmd)
+	analyzeCmd.AddCommand(analyzeMnemonicCmd)
+
+	langs := strings.Join(bip39Languages(), "|")
+	createMnemonicCmd.Flags().IntP("words", "w", 24, "number of words (12|15|18|21|24)")
+	createMnemonicCmd.Flags().String("lang", "english", "word list language ("+langs+")")
+	createMnemonicCmd.Flags().IntP("count", "c", 1, "number of mnemonics to generate")
+	createMnemonicCmd.Flags().Bool("json", false, "output as JSON array")
+	addOutputFlags(createMnemonicCmd)
+
+	analyzeMnemonicCmd.Flags().String("lang", "english", "word list language ("+langs+")")
+	analyzeMnemonicCmd.Flags().Bool("stdin", false, "read mnemonic from STDIN")
+	analyzeMnemonicCmd.Flags().Bool("json", false, "output as JSON")
+}
@@ -0,0 +1,327 @@
+// cmd/output.go
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormats lists the formats accepted by --output on create commands.
+// text keeps each command's own layout; the others render SecretItems.
+var outputFormats = []string{"text", "json", "ndjson", "yaml", "csv", "tsv", "env", "table"}
+
+// OutputConfig selects how a create command renders what it generated
+type OutputConfig struct {
+	Format   string
+	Template string
+	Dir      string
+	Force    bool
+}
+
+// SecretItem is one generated value with the metadata shared by every
+// structured output format
+type SecretItem struct {
+	Name        string    `json:"name" yaml:"name"`
+	Type        string    `json:"type" yaml:"type"`
+	Value       string    `json:"value" yaml:"value"`
+	Length      int       `json:"length" yaml:"length"`
+	EntropyBits float64   `json:"entropy_bits,omitempty" yaml:"entropy_bits,omitempty"`
+	Created     time.Time `json:"created" yaml:"created"`
+	User        string    `json:"user,omitempty" yaml:"user,omitempty"`
+	Hash        string    `json:"hash,omitempty" yaml:"hash,omitempty"`
+	Credential  string    `json:"credential,omitempty" yaml:"credential,omitempty"`
+}
+
+// addOutputFlags registers the output flags on a create subcommand. Commands
+// without their own --out get one that captures their printed output.
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().String("output", "text", "output format ("+strings.Join(commandOutputFormats(cmd), "|")+")")
+	cmd.Flags().String("template", "", "render each item with a Go template, e.g. '{{.Name}}={{.Value}}'")
+	cmd.Flags().String("out-dir", "", "write each value to its own 0600 file in this directory, named after the item")
+	cmd.Flags().Bool("force", false, "overwrite existing output files")
+	cmd.Flags().StringArray("encrypt-to", nil, "encrypt all output to this age recipient (age1..., repeatable)")
+	cmd.Flags().StringArray("recipient-file", nil, "encrypt all output to the age recipients or OpenPGP public key in this file (repeatable)")
+	cmd.RunE = withEncryption(cmd.RunE)
+	if cmd.Flags().Lookup("out") == nil {
+		cmd.Flags().StringP("out", "o", "", "write the output to this file atomically with 0600 permissions")
+		cmd.RunE = withOutFile(cmd.RunE)
+	}
+}
+
+// commandOutputFormats lists the --output formats cmd accepts, including the
+// sheet formats on commands annotated for them
+func commandOutputFormats(cmd *cobra.Command) []string {
+	if cmd.Annotations[sheetAnnotation] == "true" {
+		return append(slices.Clip(outputFormats), sheetFormats...)
+	}
+	return outputFormats
+}
+
+// getOutputConfigFromFlags extracts the output configuration from command flags
+func getOutputConfigFromFlags(cmd *cobra.Command) OutputConfig {
+	format, _ := cmd.Flags().GetString("output")
+	tmpl, _ := cmd.Flags().GetString("template")
+	dir, _ := cmd.Flags().GetString("out-dir")
+	force, _ := cmd.Flags().GetBool("force")
+
+	return OutputConfig{
+		Format:   strings.ToLower(format),
+		Template: tmpl,
+		Dir:      dir,
+		Force:    force,
+	}
+}
+
+// validateOutputFlags checks --output and --template before anything is
+// generated, so a typo does not consume ledger entries or write key files
+func validateOutputFlags(cmd *cobra.Command) error {
+	if cmd.Flags().Lookup("output") == nil {
+		return nil
+	}
+	o := getOutputConfigFromFlags(cmd)
+	formats := commandOutputFormats(cmd)
+	valid := false
+	for _, f := range formats {
+		valid = valid || f == o.Format
+	}
+	if !valid {
+		return fmt.Errorf("unsupported output %q (want one of: %s)", o.Format, strings.Join(formats, ", "))
+	}
+	if cmd.Flags().Changed("json") && cmd.Flags().Changed("output") {
+		return fmt.Errorf("--json and --output cannot be combined")
+	}
+	if o.Dir != "" {
+		for _, f := range []string{"out", "output", "template", "json"} {
+			if cmd.Flags().Changed(f) {
+				return fmt.Errorf("--out-dir cannot be combined with --%s", f)
+			}
+		}
+	}
+	if cmd.Flags().Changed("encrypt-to") || cmd.Flags().Changed("recipient-file") {
+		// These files are read by other programs, which cannot decrypt them
+		for _, f := range []string{"compose", "hash-out", "qr-png"} {
+			if cmd.Flags().Changed(f) {
+				return fmt.Errorf("--%s cannot be combined with --encrypt-to or --recipient-file", f)
+			}
+		}
+	}
+	if o.Template != "" {
+		if o.Format != "text" {
+			return fmt.Errorf("--template cannot be combined with --output %s", o.Format)
+		}
+		if _, err := template.New("item").Parse(o.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	return nil
+}
+
+// structured reports whether output goes through printSecretItems instead
+// of the command's own text or JSON layout
+func (o OutputConfig) structured() bool {
+	return (o.Format != "" && o.Format != "text") || o.Template != "" || o.Dir != ""
+}
+
+// newSecretItem describes a generated value. bits is the entropy of the
+// random input it came from; zero for public material.
+func newSecretItem(typ, value string, bits float64) SecretItem {
+	return SecretItem{
+		Type:        typ,
+		Value:       value,
+		Length:      len(value),
+		EntropyBits: math.Round(bits*100) / 100,
+		Created:     time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// newSecretItems describes a batch of values of the same type
+func newSecretItems(typ string, values []string, bits float64) []SecretItem {
+	items := make([]SecretItem, 0, len(values))
+	for _, v := range values {
+		items = append(items, newSecretItem(typ, v, bits))
+	}
+	return items
+}
+
+// nameSecretItems names each item after its type, numbering types that
+// occur more than once (strong_1, strong_2, ...)
+func nameSecretItems(items []SecretItem) {
+	total := map[string]int{}
+	for _, it := range items {
+		total[it.Type]++
+	}
+	seen := map[string]int{}
+	for i, it := range items {
+		if it.Name != "" {
+			continue
+		}
+		items[i].Name = it.Type
+		if total[it.Type] > 1 {
+			seen[it.Type]++
+			items[i].Name = fmt.Sprintf("%s_%d", it.Type, seen[it.Type])
+		}
+	}
+}
+
+// printSecretItems renders items in the configured format
+func printSecretItems(w io.Writer, items []SecretItem, o OutputConfig) error {
+	nameSecretItems(items)
+	if o.Dir != "" {
+		return writeSecretItems(w, items, o.Dir, o.Force)
+	}
+
+	if o.Template != "" {
+		tmpl, err := template.New("item").Parse(o.Template)
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+		for _, it := range items {
+			var b strings.Builder
+			if err := tmpl.Execute(&b, it); err != nil {
+				return fmt.Errorf("failed to render template: %w", err)
+			}
+			fmt.Fprintln(w, strings.TrimSuffix(b.String(), "\n"))
+		}
+		return nil
+	}
+
+	switch o.Format {
+	case "json":
+		return printJSON(w, items, "items")
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		for _, it := range items {
+			if err := enc.Encode(it); err != nil {
+				return fmt.Errorf("failed to marshal item to JSON: %w", err)
+			}
+		}
+		return nil
+	case "yaml":
+		return printYAML(w, items, "items")
+	case "csv", "tsv":
+		cw := csv.NewWriter(w)
+		if o.Format == "tsv" {
+			cw.Comma = '\t'
+		}
+		cols := itemColumns(items)
+		cw.Write(cols)
+		for _, it := range items {
+			cw.Write(itemRow(it, cols))
+		}
+		cw.Flush()
+		return cw.Error()
+	case "env":
+		for _, it := range items {
+			fmt.Fprintf(w, "%s=%s\n", envName(it.Name), envQuote(it.Value))
+		}
+		return nil
+	case "table":
+		cols := itemColumns(items)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(cols, "\t")))
+		for _, it := range items {
+			row := itemRow(it, cols)
+			for i := range row {
+				row[i] = strings.ReplaceAll(row[i], "\n", `\n`)
+			}
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unsupported output %q", o.Format)
+}
+
+// itemColumns returns the CSV/table columns, adding user, hash and
+// credential only when some item has them
+func itemColumns(items []SecretItem) []string {
+	cols := []string{"name", "type", "length", "entropy_bits", "created", "value"}
+	for _, c := range []string{"user", "hash", "credential"} {
+		for _, it := range items {
+			if itemRow(it, []string{c})[0] != "" {
+				cols = append(cols, c)
+				break
+			}
+		}
+	}
+	return cols
+}
+
+// itemRow returns the item's fields in column order
+func itemRow(it SecretItem, cols []string) []string {
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		switch c {
+		case "name":
+			row[i] = it.Name
+		case "type":
+			row[i] = it.Type
+		case "length":
+			row[i] = fmt.Sprint(it.Length)
+		case "entropy_bits":
+			if it.EntropyBits > 0 {
+				row[i] = fmt.Sprintf("%.2f", it.EntropyBits)
+			}
+		case "created":
+			row[i] = it.Created.Format(time.RFC3339)
+		case "value":
+			row[i] = it.Value
+		case "user":
+			row[i] = it.User
+		case "hash":
+			row[i] = it.Hash
+		case "credential":
+			row[i] = it.Credential
+		}
+	}
+	return row
+}
+
+// envName turns an item name into an environment variable name
+func envName(name string) string {
+	b := []byte(strings.ToUpper(name))
+	for i, c := range b {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	if len(b) > 0 && b[0] >= '0' && b[0] <= '9' {
+		return "_" + string(b)
+	}
+	return string(b)
+}
+
+// envQuote quotes a value for a dotenv file: bare when safe, single quotes
+// (no interpolation) when possible, otherwise double quotes with escapes
+func envQuote(v string) string {
+	if v != "" && strings.IndexFunc(v, func(r rune) bool {
+		return !(r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-.,:/+=@", r)))
+	}) < 0 {
+		return v
+	}
+	if !strings.ContainsAny(v, "'\n") {
+		return "'" + v + "'"
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`)
+	return `"` + r.Replace(v) + `"`
+}
+
+// printYAML outputs v as YAML with two-space indentation
+func printYAML(w io.Writer, v any, what string) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to marshal %s to YAML: %w", what, err)
+	}
+	return enc.Close()
+}
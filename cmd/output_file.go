@@ -0,0 +1,153 @@
+// cmd/output_file.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// withOutFile wraps a create command so that --out captures everything it
+// writes to cmd.OutOrStdout() in a 0600 file, moved into place only on success
+func withOutFile(run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("out")
+		if path == "" {
+			return run(cmd, args)
+		}
+		force, _ := cmd.Flags().GetBool("force")
+
+		f, err := createSecretTemp(path, 0o600, force)
+		if err != nil {
+			return err
+		}
+		defer redirectOut(cmd, f)()
+
+		if err := run(cmd, args); err != nil {
+			f.Close()
+			os.Remove(f.Name())
+			return err
+		}
+		return commitSecretFile(f, path, force)
+	}
+}
+
+// redirectOut sends cmd's output to w and returns a function that restores
+// it, leaving usage and help on stderr when no writer was set before
+func redirectOut(cmd *cobra.Command, w io.Writer) func() {
+	var prev io.Writer
+	if cmd.OutOrStderr() != os.Stderr {
+		prev = cmd.OutOrStdout()
+	}
+	cmd.SetOut(w)
+	return func() { cmd.SetOut(prev) }
+}
+
+// writeSecretItems writes each item's value to its own 0600 file named after
+// the item, the layout of Docker and Kubernetes secret mounts
+func writeSecretItems(w io.Writer, items []SecretItem, dir string, overwrite bool) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// Check every name first so a conflict does not leave a partial set
+	for _, it := range items {
+		if err := checkOverwrite(filepath.Join(dir, it.Name), overwrite); err != nil {
+			return err
+		}
+	}
+	for _, it := range items {
+		path := filepath.Join(dir, it.Name)
+		if err := writeSecretFile(path, []byte(it.Value), 0o600, overwrite); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, path)
+	}
+	return nil
+}
+
+// writeSecretFile atomically writes data to path with the given permissions.
+// An existing file is only replaced when overwrite is set, and private files
+// are encrypted first when the command has recipients.
+func writeSecretFile(path string, data []byte, perm os.FileMode, overwrite bool) error {
+	if secretRecipients != nil && perm&0o077 == 0 {
+		ciphertext, err := secretRecipients.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		data = ciphertext
+	}
+	f, err := createSecretTemp(path, perm, overwrite)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return commitSecretFile(f, path, overwrite)
+}
+
+// createSecretTemp opens a temporary file with perm next to path, so the
+// final rename stays on one filesystem and readers never see partial data
+func createSecretTemp(path string, perm os.FileMode, overwrite bool) (*os.File, error) {
+	if err := checkOverwrite(path, overwrite); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// commitSecretFile syncs and closes the temporary file and moves it to path.
+// Without overwrite it is hard-linked into place, which fails if path has
+// appeared since createSecretTemp checked, instead of racing a rename.
+func commitSecretFile(f *os.File, path string, overwrite bool) error {
+	err := f.Sync()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if overwrite {
+		if err := os.Rename(f.Name(), path); err != nil {
+			os.Remove(f.Name())
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	}
+	err = os.Link(f.Name(), path)
+	os.Remove(f.Name())
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// checkOverwrite refuses to replace an existing path unless overwrite is set
+func checkOverwrite(path string, overwrite bool) error {
+	if overwrite {
+		return nil
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+// cmd/pdf.go
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// A minimal PDF 1.4 writer for printable sheets. It only uses the standard
+// Helvetica and Courier fonts, so nothing needs to be embedded, and draws QR
+// codes as vector modules.
+
+// pdfPaperSizes are page sizes in points
+var pdfPaperSizes = map[string][2]float64{
+	"letter": {612, 792},
+	"a4":     {595.28, 841.89},
+}
+
+// pdfFonts maps resource names to the standard fonts they select
+var pdfFonts = [][2]string{
+	{"F1", "Helvetica"},
+	{"F2", "Helvetica-Bold"},
+	{"F3", "Courier"},
+	{"F4", "Courier-Bold"},
+}
+
+// courierAdvance is the width of every Courier glyph as a fraction of the size
+const courierAdvance = 0.6
+
+// pdfDoc accumulates page content streams. The first text that cannot be
+// encoded is kept in err and reported by bytes.
+type pdfDoc struct {
+	width, height float64
+	pages         []*bytes.Buffer
+	err           error
+}
+
+// page returns the content stream of the current page
+func (d *pdfDoc) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// addPage starts a new blank page
+func (d *pdfDoc) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// text draws s with its baseline starting at x, y
+func (d *pdfDoc) text(x, y float64, font string, size float64, s string) {
+	enc, err := pdfEncode(s)
+	if err != nil {
+		if d.err == nil {
+			d.err = err
+		}
+		return
+	}
+	r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+	fmt.Fprintf(d.page(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, r.Replace(enc))
+}
+
+// pdfEncode converts UTF-8 text to WinAnsiEncoding (Windows-1252), the
+// encoding of the standard fonts
+func pdfEncode(s string) (string, error) {
+	enc, err := charmap.Windows1252.NewEncoder().String(s)
+	if err != nil {
+		return "", fmt.Errorf("%q cannot be printed in a PDF (only Windows-1252 characters are supported)", s)
+	}
+	return enc, nil
+}
+
+// fill sets the fill color used by text and rect
+func (d *pdfDoc) fill(r, g, b float64) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f rg\n", r, g, b)
+}
+
+// rect draws a filled rectangle
+func (d *pdfDoc) rect(x, y, w, h float64) {
+	fmt.Fprintf(d.page(), "%.2f %.2f %.2f %.2f re f\n", x, y, w, h)
+}
+
+// line strokes a line of the given width and color
+func (d *pdfDoc) line(x1, y1, x2, y2, width float64, r, g, b float64) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S\n", r, g, b, width, x1, y1, x2, y2)
+}
+
+// bytes assembles the document: catalog, page tree, fonts, then each page
+// followed by its content stream
+func (d *pdfDoc) bytes() ([]byte, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	var b bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	firstPage := 3 + len(pdfFonts)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	var fonts []string
+	for i, f := range pdfFonts {
+		obj(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f[1]))
+		fonts = append(fonts, fmt.Sprintf("/%s %d 0 R", f[0], 3+i))
+	}
+	for i, content := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			d.width, d.height, strings.Join(fonts, " "), firstPage+2*i+1))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return b.Bytes(), nil
+}
+
+// renderSheetPDF lays the sheet out on paper, breaking pages between entries
+func renderSheetPDF(sheet Sheet, paper string) ([]byte, error) {
+	size, ok := pdfPaperSizes[paper]
+	if !ok {
+		return nil, fmt.Errorf("unsupported paper %q (want letter or a4)", paper)
+	}
+	const (
+		margin    = 48.0
+		valueSize = 14.0
+		phonSize  = 8.0
+		qrSize    = 72.0
+		gap       = 12.0
+	)
+	d := &pdfDoc{width: size[0], height: size[1]}
+	textX := margin
+	if sheet.QR {
+		textX += qrSize + gap
+	}
+	textW := d.width - margin - textX
+	valuePerLine := int(textW / (valueSize * courierAdvance))
+	phonPerLine := int(textW / (phonSize * courierAdvance))
+
+	var y float64
+	newPage := func() {
+		d.addPage()
+		y = d.height - margin
+		d.fill(0, 0, 0)
+		d.text(margin, y-16, "F2", 16, sheet.Title)
+		d.text(margin, y-30, "F1", 8, fmt.Sprintf("Generated %s. Page %d", sheet.Created, len(d.pages)))
+		y -= 44
+	}
+	newPage()
+	d.text(margin, y, "F1", 7.5, sheet.Legend)
+	y -= 14
+
+	for _, section := range sheet.Sections {
+		// Keep a heading together with at least its first entry
+		if y-60 < margin {
+			newPage()
+		}
+		d.fill(0, 0, 0)
+		d.text(margin, y-12, "F2", 12, section.Title)
+		d.line(margin, y-16, d.width-margin, y-16, 1.2, 0, 0, 0)
+		y -= 26
+
+		for _, e := range section.Entries {
+			valueLines := chunkString(e.Value, valuePerLine)
+			phonLines := wrapWords(e.Phonetic, phonPerLine)
+			height := 10 + float64(len(valueLines))*valueSize*1.25 + float64(len(phonLines))*phonSize*1.25
+			if sheet.QR {
+				height = math.Max(height, qrSize)
+			}
+			if y-height-gap < margin {
+				newPage()
+			}
+
+			top := y
+			d.fill(0.4, 0.4, 0.4)
+			d.text(textX, y-7, "F1", 7, e.Label)
+			y -= 10
+			for _, line := range valueLines {
+				y -= valueSize
+				drawSheetValue(d, textX, y, valueSize, line)
+				y -= valueSize * 0.25
+			}
+			d.fill(0.2, 0.2, 0.2)
+			for _, line := range phonLines {
+				y -= phonSize
+				d.text(textX, y, "F3", phonSize, line)
+				y -= phonSize * 0.25
+			}
+			if sheet.QR {
+				if err := drawQR(d, margin, top-qrSize, qrSize, e.Value); err != nil {
+					return nil, err
+				}
+			}
+			y = math.Min(y, top-height) - gap/2
+			d.line(margin, y, d.width-margin, y, 0.3, 0.7, 0.7, 0.7)
+			y -= gap / 2
+		}
+		y -= gap
+	}
+	return d.bytes()
+}
+
+// drawSheetValue draws a value one character at a time in bold Courier,
+// coloring digits and symbols and slashing zeros
+func drawSheetValue(d *pdfDoc, x, y, size float64, s string) {
+	advance := size * courierAdvance
+	for i := 0; i < len(s); i++ {
+		cx := x + float64(i)*advance
+		switch sheetCharClass(s[i]) {
+		case "d":
+			d.fill(0.024, 0.27, 0.68)
+		case "s":
+			d.fill(0.69, 0, 0)
+		default:
+			d.fill(0, 0, 0)
+		}
+		d.text(cx, y, "F4", size, string(s[i]))
+		if s[i] == '0' {
+			d.line(cx+advance*0.2, y-size*0.05, cx+advance*0.8, y+size*0.65, size*0.07, 0.024, 0.27, 0.68)
+		}
+	}
+}
+
+// drawQR draws content as a QR code with its bottom-left corner at x, y
+func drawQR(d *pdfDoc, x, y, size float64, content string) error {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	bitmap := qr.Bitmap() // includes the quiet zone scanners need
+	module := size / float64(len(bitmap))
+	d.fill(0, 0, 0)
+	for row, cells := range bitmap {
+		for col, dark := range cells {
+			if dark {
+				d.rect(x+float64(col)*module, y+size-float64(row+1)*module, module, module)
+			}
+		}
+	}
+	return nil
+}
+
+// chunkString splits s into pieces of at most n bytes
+func chunkString(s string, n int) []string {
+	var out []string
+	for len(s) > n {
+		out = append(out, s[:n])
+		s = s[n:]
+	}
+	return append(out, s)
+}
+
+// wrapWords wraps space-separated words into lines of at most n characters
+func wrapWords(s string, n int) []string {
+	var lines []string
+	line := ""
+	for _, w := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(w) > n {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += w
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,30 @@
+// cmd/qrcode.go
+package cmd
+
+import (
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// qrPNGSize is the edge length in pixels of generated QR code images
+const qrPNGSize = 512
+
+// renderQRTerminal renders content as a QR code using half-block characters
+func renderQRTerminal(content string) (string, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return qr.ToSmallString(false), nil
+}
+
+// writeQRPNG writes content as a QR code PNG with 0600 permissions, since the
+// payload is usually a secret
+func writeQRPNG(path, content string, overwrite bool) error {
+	png, err := qrcode.Encode(content, qrcode.Medium, qrPNGSize)
+	if err != nil {
+		return fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return writeSecretFile(path, png, 0o600, overwrite)
+}
@@ -0,0 +1,128 @@
+// cmd/report.go
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// reportFormats lists the formats accepted by --format on scanning and
+// audit commands
+var reportFormats = []string{"text", "json", "sarif", "junit"}
+
+// checkResult is one audited item in a format-neutral shape, converted to
+// SARIF results or JUnit test cases
+type checkResult struct {
+	Name        string
+	Rule        string
+	Passed      bool
+	Message     string
+	Path        string
+	Line        int
+	Column      int
+	Fingerprint string
+}
+
+// getReportFormat reads --format, honoring --json as a shorthand where the
+// command has it
+func getReportFormat(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		format = "json"
+	}
+	format = strings.ToLower(format)
+	for _, f := range reportFormats {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported format %q (want one of: %s)", format, strings.Join(reportFormats, ", "))
+}
+
+// addReportFormatFlag registers --format on an audit command
+func addReportFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("format", "text", "report format ("+strings.Join(reportFormats, "|")+")")
+}
+
+// printSARIF writes the failed results as a SARIF 2.1.0 log
+func printSARIF(results []checkResult, rules []sarifRule) error {
+	log := newSARIFLog(rules)
+	for _, r := range results {
+		if r.Passed {
+			continue
+		}
+		res := sarifResult{
+			RuleID:  r.Rule,
+			Level:   "error",
+			Message: sarifMessage{Text: r.Message},
+		}
+		if r.Path != "" {
+			loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: r.Path}}
+			if r.Line > 0 {
+				loc.Region = &sarifRegion{StartLine: r.Line, StartColumn: r.Column}
+			}
+			res.Locations = []sarifLocation{{PhysicalLocation: loc}}
+		}
+		if r.Fingerprint != "" {
+			res.PartialFingerprints = map[string]string{"secretHash/v1": r.Fingerprint}
+		}
+		log.Runs[0].Results = append(log.Runs[0].Results, res)
+	}
+	return printJSON(os.Stdout, log, "SARIF report")
+}
+
+// JUnit XML as read by Jenkins, GitLab and GitHub test reporters
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// printJUnit writes the results as one JUnit test suite. An empty result set
+// becomes a single passing case so CI still records the run.
+func printJUnit(suite string, results []checkResult) error {
+	if len(results) == 0 {
+		results = []checkResult{{Name: "no findings", Passed: true}}
+	}
+	ts := junitTestSuite{Name: suite, Tests: len(results)}
+	for _, r := range results {
+		tc := junitTestCase{Name: r.Name, ClassName: suite}
+		if !r.Passed {
+			ts.Failures++
+			tc.Failure = &junitFailure{Message: r.Message, Type: r.Rule, Text: r.Message}
+		}
+		ts.TestCases = append(ts.TestCases, tc)
+	}
+
+	out, err := xml.MarshalIndent(junitTestSuites{Tests: ts.Tests, Failures: ts.Failures, Suites: []junitTestSuite{ts}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	fmt.Print(xml.Header)
+	fmt.Println(string(out))
+	return nil
+}
@@ -0,0 +1,331 @@
+// cmd/scan.go
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/spf13/cobra"
+)
+
+// Files that are full of hashes by design and would only produce noise
+var scanSkipFiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"composer.lock":     true,
+	"Gemfile.lock":      true,
+}
+
+// ScanConfig holds configuration for secret scanning
+type ScanConfig struct {
+	Format      string
+	MinEntropy  float64
+	MaxSize     int64
+	NoGitignore bool
+	History     bool
+	Baseline    string
+}
+
+// Finding is one secret found by a scan
+type Finding struct {
+	Path        string  `json:"path"`
+	Line        int     `json:"line"`
+	Column      int     `json:"column"`
+	Rule        string  `json:"rule"`
+	Description string  `json:"description"`
+	Secret      string  `json:"secret"`
+	Entropy     float64 `json:"entropy"`
+	Fingerprint string  `json:"fingerprint"`
+	Commit      string  `json:"commit,omitempty"`
+	Author      string  `json:"author,omitempty"`
+	Date        string  `json:"date,omitempty"`
+}
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [path]",
+	Short: "Scan files for secrets",
+	Long: `Walk a file or directory (respecting .gitignore) and report likely secrets:
+private key blocks, AWS keys, JWTs, GitHub/Slack/Stripe/Google tokens, age
+identities, keyforge tokens with a valid checksum, credential assignments,
+and other high-entropy strings.
+
+--history scans the git repository containing the path instead: every
+commit on every branch and tag, reporting the commit, author and file where
+each secret first appeared.
+
+Secrets are redacted in the report. Add "keyforge:allow" to a line to
+suppress findings on it, or pass --baseline with a previous JSON report or
+an allowlist of sha256:<fingerprint>, path:<glob> and rule:<id> entries.
+The command fails when anything is found.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := getScanConfigFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		root := "."
+		if len(args) == 1 {
+			root = args[0]
+		}
+
+		var findings []Finding
+		if cfg.History {
+			findings, err = scanHistory(root, cfg)
+		} else {
+			findings, err = scanPath(root, cfg)
+		}
+		if err != nil {
+			return err
+		}
+		if cfg.Baseline != "" {
+			baseline, err := loadBaseline(cfg.Baseline)
+			if err != nil {
+				return err
+			}
+			findings = baseline.filter(findings)
+		}
+		if err := printFindings(findings, cfg.Format); err != nil {
+			return err
+		}
+		if len(findings) > 0 {
+			return fmt.Errorf("found %d potential secrets", len(findings))
+		}
+		return nil
+	},
+}
+
+// getScanConfigFromFlags extracts configuration from command flags
+func getScanConfigFromFlags(cmd *cobra.Command) (ScanConfig, error) {
+	format, err := getReportFormat(cmd)
+	minEntropy, _ := cmd.Flags().GetFloat64("min-entropy")
+	maxSize, _ := cmd.Flags().GetInt64("max-size")
+	noGitignore, _ := cmd.Flags().GetBool("no-gitignore")
+	history, _ := cmd.Flags().GetBool("history")
+	baseline, _ := cmd.Flags().GetString("baseline")
+
+	return ScanConfig{
+		Format:      format,
+		MinEntropy:  minEntropy,
+		MaxSize:     maxSize,
+		NoGitignore: noGitignore,
+		History:     history,
+		Baseline:    baseline,
+	}, err
+}
+
+// scanPath scans a single file or walks a directory tree. Unreadable files
+// and directories inside the tree are reported on stderr and skipped.
+func scanPath(root string, cfg ScanConfig) ([]Finding, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+	if !info.IsDir() {
+		return scanFile(root, cfg)
+	}
+
+	var patterns []gitignore.Pattern
+	if !cfg.NoGitignore {
+		patterns = readIgnorePatterns(filepath.Join(root, ".git", "info", "exclude"), nil)
+	}
+
+	var findings []Finding
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", path, err)
+			return nil
+		}
+		var parts []string
+		if rel, _ := filepath.Rel(root, path); rel != "." {
+			parts = strings.Split(filepath.ToSlash(rel), "/")
+			if d.IsDir() && d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if gitignore.NewMatcher(patterns).Match(parts, d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if d.IsDir() {
+			// Patterns are scoped to their directory, so later siblings are unaffected
+			if !cfg.NoGitignore {
+				patterns = append(patterns, readIgnorePatterns(filepath.Join(path, ".gitignore"), parts)...)
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || scanSkipFiles[d.Name()] {
+			return nil
+		}
+		found, err := scanFile(path, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", path, err)
+			return nil
+		}
+		findings = append(findings, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+	return findings, nil
+}
+
+// readIgnorePatterns parses a gitignore-style file whose patterns apply
+// below domain. A missing file has no patterns; an unreadable one is
+// reported on stderr and ignored.
+func readIgnorePatterns(path string, domain []string) []gitignore.Pattern {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", path, err)
+		}
+		return nil
+	}
+	var patterns []gitignore.Pattern
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+	return patterns
+}
+
+// scanFile scans one file, skipping binaries and files over cfg.MaxSize
+func scanFile(path string, cfg ScanConfig) ([]Finding, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxSize > 0 && info.Size() > cfg.MaxSize {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return scanContent(filepath.ToSlash(path), data, cfg.MinEntropy), nil
+}
+
+// scanContent runs the detectors over every line of a text blob. PEM block
+// bodies are skipped; a private key is reported once, at its header.
+func scanContent(path string, data []byte, minEntropy float64) []Finding {
+	if isBinary(data) {
+		return nil
+	}
+	var findings []Finding
+	inPEM := false
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		if inPEM {
+			inPEM = !strings.Contains(line, "-----END ")
+			continue
+		}
+		inPEM = strings.Contains(line, "-----BEGIN ") && !strings.Contains(line, "-----END ")
+		for _, m := range findSecrets(line, minEntropy) {
+			findings = append(findings, Finding{
+				Path:        path,
+				Line:        n,
+				Column:      m.Column,
+				Rule:        m.Rule,
+				Description: m.Description,
+				Secret:      redactSecret(m.Secret),
+				Entropy:     m.Entropy,
+				Fingerprint: secretFingerprint(m.Secret),
+			})
+		}
+	}
+	return findings
+}
+
+// isBinary reports whether data looks like a binary file (NUL in the first 8 KiB)
+func isBinary(data []byte) bool {
+	if len(data) > 8000 {
+		data = data[:8000]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// printFindings outputs the findings as text, JSON, SARIF or JUnit
+func printFindings(findings []Finding, format string) error {
+	switch format {
+	case "json":
+		if findings == nil {
+			findings = []Finding{}
+		}
+		return printJSON(os.Stdout, findings, "findings")
+	case "sarif":
+		return printSARIF(findingResults(findings), scanRules())
+	case "junit":
+		return printJUnit("keyforge scan", findingResults(findings))
+	}
+
+	for _, f := range findings {
+		if f.Commit != "" {
+			fmt.Printf("%s %s %s ", f.Commit[:7], f.Date, f.Author)
+		}
+		fmt.Printf("%s:%d:%d: %s: %s (%s, entropy %.2f)\n", f.Path, f.Line, f.Column, f.Rule, f.Description, f.Secret, f.Entropy)
+	}
+	if len(findings) == 0 {
+		fmt.Println("No secrets found.")
+	}
+	return nil
+}
+
+// scanRules describes every detector for SARIF consumers
+func scanRules() []sarifRule {
+	rules := make([]sarifRule, 0, len(secretDetectors)+1)
+	for _, d := range secretDetectors {
+		rules = append(rules, sarifRule{ID: d.ID, ShortDescription: sarifMessage{Text: d.Description}})
+	}
+	return append(rules, sarifRule{ID: "high-entropy", ShortDescription: sarifMessage{Text: "High-entropy string"}})
+}
+
+// findingResults converts findings into failed check results
+func findingResults(findings []Finding) []checkResult {
+	results := make([]checkResult, 0, len(findings))
+	for _, f := range findings {
+		msg := fmt.Sprintf("%s: %s", f.Description, f.Secret)
+		if f.Commit != "" {
+			msg += fmt.Sprintf(" (introduced in %s by %s on %s)", f.Commit[:7], f.Author, f.Date)
+		}
+		results = append(results, checkResult{
+			Name:        fmt.Sprintf("%s:%d:%d", f.Path, f.Line, f.Column),
+			Rule:        f.Rule,
+			Message:     msg,
+			Path:        f.Path,
+			Line:        f.Line,
+			Column:      f.Column,
+			Fingerprint: f.Fingerprint,
+		})
+	}
+	return results
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().String("format", "text", "report format ("+strings.Join(reportFormats, "|")+")")
+	scanCmd.Flags().Float64("min-entropy", defaultMinEntropy, "minimum Shannon entropy (bits/char) for generic high-entropy findings")
+	scanCmd.Flags().Int64("max-size", 1<<20, "skip files larger than this many bytes (0 for no limit)")
+	scanCmd.Flags().Bool("no-gitignore", false, "scan files ignored by .gitignore too")
+	scanCmd.Flags().Bool("history", false, "scan the git history (all branches and tags) instead of the working tree")
+	scanCmd.Flags().String("baseline", "", "suppress findings listed in this JSON report or allowlist file")
+}
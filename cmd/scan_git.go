@@ -0,0 +1,205 @@
+// cmd/scan_git.go
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// scanHistory scans the lines added by every commit reachable from any ref
+// and reports each secret once, at the commit where it first appeared
+func scanHistory(root string, cfg ScanConfig) ([]Finding, error) {
+	repo, err := git.PlainOpenWithOptions(root, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository: %w", err)
+	}
+	commits, err := repo.Log(&git.LogOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	first := map[string]Finding{}
+	err = commits.ForEach(func(c *object.Commit) error {
+		found, err := scanCommit(c, cfg)
+		if err != nil {
+			return fmt.Errorf("commit %s: %w", c.Hash.String()[:7], err)
+		}
+		for _, f := range found {
+			key := f.Fingerprint + "\x00" + f.Path
+			if prev, ok := first[key]; !ok || f.Date < prev.Date {
+				first[key] = f
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan git history: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(first))
+	for _, f := range first {
+		findings = append(findings, f)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Date != findings[j].Date {
+			return findings[i].Date < findings[j].Date
+		}
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// scanCommit scans the lines a commit added relative to its first parent
+func scanCommit(c *object.Commit, cfg ScanConfig) ([]Finding, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	parentTree := &object.Tree{}
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, fp := range patch.FilePatches() {
+		_, to := fp.Files()
+		if to == nil || fp.IsBinary() || scanSkipFiles[path.Base(to.Path())] {
+			continue
+		}
+		for _, f := range scanAddedLines(to.Path(), fp.Chunks(), cfg.MinEntropy) {
+			f.Commit = c.Hash.String()
+			f.Author = fmt.Sprintf("%s <%s>", c.Author.Name, c.Author.Email)
+			f.Date = c.Author.When.UTC().Format(time.RFC3339)
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// scanAddedLines runs the detectors over added chunks, tracking line numbers
+// in the new version of the file
+func scanAddedLines(name string, chunks []diff.Chunk, minEntropy float64) []Finding {
+	var findings []Finding
+	line := 0
+	for _, ch := range chunks {
+		if ch.Type() == diff.Delete {
+			continue
+		}
+		content := ch.Content()
+		n := strings.Count(content, "\n")
+		if !strings.HasSuffix(content, "\n") {
+			n++
+		}
+		if ch.Type() == diff.Add {
+			for _, f := range scanContent(name, []byte(content), minEntropy) {
+				f.Line += line
+				findings = append(findings, f)
+			}
+		}
+		line += n
+	}
+	return findings
+}
+
+// Baseline suppresses accepted findings. It is either a JSON findings report
+// (scan --format json) or a text file of entries, one per line:
+//
+//	sha256:<fingerprint>   a specific secret
+//	path:<glob>            every finding in matching files
+//	rule:<id>              every finding of a rule
+type Baseline struct {
+	Fingerprints map[string]bool
+	Paths        []string
+	Rules        map[string]bool
+}
+
+// loadBaseline reads a baseline or allowlist file
+func loadBaseline(name string) (*Baseline, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	b := &Baseline{Fingerprints: map[string]bool{}, Rules: map[string]bool{}}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var findings []Finding
+		if err := json.Unmarshal(trimmed, &findings); err != nil {
+			return nil, fmt.Errorf("failed to parse baseline: %w", err)
+		}
+		for _, f := range findings {
+			b.Fingerprints[f.Fingerprint] = true
+		}
+		return b, nil
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		entry := strings.TrimSpace(s.Text())
+		switch {
+		case entry == "" || strings.HasPrefix(entry, "#"):
+		case strings.HasPrefix(entry, "sha256:"):
+			b.Fingerprints[entry] = true
+		case strings.HasPrefix(entry, "path:"):
+			b.Paths = append(b.Paths, strings.TrimPrefix(entry, "path:"))
+		case strings.HasPrefix(entry, "rule:"):
+			b.Rules[strings.TrimPrefix(entry, "rule:")] = true
+		default:
+			return nil, fmt.Errorf("invalid baseline entry %q (want sha256:, path: or rule:)", entry)
+		}
+	}
+	return b, s.Err()
+}
+
+// filter drops findings covered by the baseline
+func (b *Baseline) filter(findings []Finding) []Finding {
+	kept := findings[:0]
+	for _, f := range findings {
+		if !b.allows(f) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+func (b *Baseline) allows(f Finding) bool {
+	if b.Fingerprints[f.Fingerprint] || b.Rules[f.Rule] {
+		return true
+	}
+	for _, p := range b.Paths {
+		if strings.HasSuffix(p, "/") && strings.HasPrefix(f.Path, p) {
+			return true
+		}
+		if ok, _ := path.Match(p, f.Path); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,284 @@
+// cmd/selftest.go
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// SelftestConfig holds configuration for the statistical self-test
+type SelftestConfig struct {
+	Samples int
+	Alpha   float64
+	AsJSON  bool
+}
+
+// SelftestResult is the outcome of one statistical check
+type SelftestResult struct {
+	Generator string  `json:"generator"`
+	Test      string  `json:"test"`
+	Statistic float64 `json:"statistic"`
+	PValue    float64 `json:"p_value"`
+	Pass      bool    `json:"pass"`
+}
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run statistical randomness checks against every generator",
+	Long: `Generate large samples from every generator and check them for bias:
+- chi-squared goodness of fit per position and per character
+- serial correlation between consecutive characters
+- NIST SP 800-22 monobit and runs tests on the WEP key bitstream
+
+Per-position results report the worst position with a Bonferroni-adjusted
+p-value. --alpha is the family-wise significance level: each check is held
+to alpha divided by the number of checks (Bonferroni), and the command exits
+non-zero if any check falls below that threshold.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getSelftestConfigFromFlags(cmd)
+		if cfg.Samples < 100 {
+			return fmt.Errorf("samples must be at least 100, got: %d", cfg.Samples)
+		}
+		if cfg.Alpha <= 0 || cfg.Alpha >= 1 {
+			return fmt.Errorf("alpha must be between 0 and 1, got: %g", cfg.Alpha)
+		}
+
+		results, err := runSelftest(cfg)
+		if err != nil {
+			return fmt.Errorf("self-test aborted: %w", err)
+		}
+		if err := printSelftestResults(results, cfg.AsJSON); err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, r := range results {
+			if !r.Pass {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("self-test failed: %d of %d checks below corrected alpha %g", failed, len(results), cfg.Alpha/float64(len(results)))
+		}
+		return nil
+	},
+}
+
+// getSelftestConfigFromFlags extracts configuration from command flags
+func getSelftestConfigFromFlags(cmd *cobra.Command) SelftestConfig {
+	samples, _ := cmd.Flags().GetInt("samples")
+	alpha, _ := cmd.Flags().GetFloat64("alpha")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	return SelftestConfig{
+		Samples: samples,
+		Alpha:   alpha,
+		AsJSON:  asJSON,
+	}
+}
+
+// runSelftest samples every generator and runs the statistical checks
+func runSelftest(cfg SelftestConfig) ([]SelftestResult, error) {
+	var results []SelftestResult
+
+	easy, err := sampleGenerator(cfg.Samples, func() (string, error) { return genEasyWithError(12) })
+	if err != nil {
+		return nil, fmt.Errorf("easy: %w", err)
+	}
+	results = append(results, checkCharacterGenerator("easy", easy, easyPool)...)
+
+	strong, err := sampleGenerator(cfg.Samples, func() (string, error) { return genStrongWithError(20) })
+	if err != nil {
+		return nil, fmt.Errorf("strong: %w", err)
+	}
+	results = append(results, checkCharacterGenerator("strong", strong, func(int) string { return strongPool })...)
+
+	weps := []struct {
+		name  string
+		bytes int
+	}{
+		{"64wep", 5},
+		{"128wep", 13},
+		{"256wep", 29},
+	}
+	for _, w := range weps {
+		keys, err := sampleGenerator(cfg.Samples, func() (string, error) { return genWEPHexBytesWithError(w.bytes) })
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", w.name, err)
+		}
+		wepResults, err := checkHexGenerator(w.name, keys)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", w.name, err)
+		}
+		results = append(results, wepResults...)
+	}
+
+	// Bonferroni: keep the chance of any false failure across all checks at alpha
+	threshold := cfg.Alpha / float64(len(results))
+	for i := range results {
+		results[i].Pass = results[i].PValue >= threshold
+	}
+	return results, nil
+}
+
+// sampleGenerator collects n outputs from gen
+func sampleGenerator(n int, gen func() (string, error)) ([]string, error) {
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		s, err := gen()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// checkCharacterGenerator runs per-position, per-character and serial
+// correlation checks on fixed-length samples drawn from the given pools
+func checkCharacterGenerator(name string, samples []string, poolAt func(int) string) []SelftestResult {
+	length := len(samples[0])
+
+	// Per position: each position must be uniform over its own pool
+	worstP, worstStat := 1.0, 0.0
+	for pos := 0; pos < length; pos++ {
+		pool := poolAt(pos)
+		counts := make([]int, len(pool))
+		for _, s := range samples {
+			counts[strings.IndexByte(pool, s[pos])]++
+		}
+		stat, p := chiSquared(counts)
+		if p < worstP {
+			worstP, worstStat = p, stat
+		}
+	}
+	results := []SelftestResult{{
+		Generator: name,
+		Test:      "chi-squared per position",
+		Statistic: worstStat,
+		PValue:    math.Min(1, worstP*float64(length)),
+	}}
+
+	// Per character: aggregate counts over every position sharing a pool
+	pools := map[string][]int{}
+	var order []string
+	for pos := 0; pos < length; pos++ {
+		pool := poolAt(pos)
+		if _, ok := pools[pool]; !ok {
+			pools[pool] = make([]int, len(pool))
+			order = append(order, pool)
+		}
+		for _, s := range samples {
+			pools[pool][strings.IndexByte(pool, s[pos])]++
+		}
+	}
+	for _, pool := range order {
+		stat, p := chiSquared(pools[pool])
+		results = append(results, SelftestResult{
+			Generator: name,
+			Test:      fmt.Sprintf("chi-squared per character (%d-char pool)", len(pool)),
+			Statistic: stat,
+			PValue:    p,
+		})
+	}
+
+	// Serial correlation between consecutive characters. Values are centred
+	// on their pool's mean so pools of different sizes don't look correlated.
+	var values []float64
+	for _, s := range samples {
+		for pos := 0; pos < length; pos++ {
+			pool := poolAt(pos)
+			idx := float64(strings.IndexByte(pool, s[pos]))
+			values = append(values, (idx-float64(len(pool)-1)/2)/float64(len(pool)))
+		}
+	}
+	coeff, p := serialCorrelation(values)
+	results = append(results, SelftestResult{
+		Generator: name,
+		Test:      "serial correlation",
+		Statistic: coeff,
+		PValue:    p,
+	})
+
+	return results
+}
+
+// checkHexGenerator runs nibble, byte, correlation and NIST bitstream checks
+// on hex-encoded key samples
+func checkHexGenerator(name string, samples []string) ([]SelftestResult, error) {
+	var data []byte
+	nibbles := make([]int, 16)
+	for _, s := range samples {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex output %q: %w", s, err)
+		}
+		data = append(data, b...)
+		for _, c := range b {
+			nibbles[c>>4]++
+			nibbles[c&0x0f]++
+		}
+	}
+
+	bytesCount := make([]int, 256)
+	values := make([]float64, len(data))
+	for i, c := range data {
+		bytesCount[c]++
+		values[i] = float64(c)
+	}
+
+	var results []SelftestResult
+	add := func(test string, stat, p float64) {
+		results = append(results, SelftestResult{Generator: name, Test: test, Statistic: stat, PValue: p})
+	}
+
+	stat, p := chiSquared(nibbles)
+	add("chi-squared per hex digit", stat, p)
+	stat, p = chiSquared(bytesCount)
+	add("chi-squared per byte", stat, p)
+	stat, p = serialCorrelation(values)
+	add("serial correlation", stat, p)
+
+	bits := bytesToBits(data)
+	stat, p = monobitTest(bits)
+	add("NIST monobit frequency", stat, p)
+	stat, p = runsTest(bits)
+	add("NIST runs", stat, p)
+
+	return results, nil
+}
+
+// printSelftestResults outputs the check results as a table or JSON
+func printSelftestResults(results []SelftestResult, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal self-test results to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%-8s %-40s %12s %10s  %s\n", "GEN", "TEST", "STATISTIC", "P-VALUE", "RESULT")
+	for _, r := range results {
+		verdict := "PASS"
+		if !r.Pass {
+			verdict = "FAIL"
+		}
+		fmt.Printf("%-8s %-40s %12.4f %10.4f  %s\n", r.Generator, r.Test, r.Statistic, r.PValue, verdict)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+
+	selftestCmd.Flags().IntP("samples", "n", 10000, "number of values to sample from each generator")
+	selftestCmd.Flags().Float64("alpha", 0.001, "family-wise significance level, split across all checks")
+	selftestCmd.Flags().Bool("json", false, "output results as JSON")
+}
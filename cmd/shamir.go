@@ -0,0 +1,125 @@
+// cmd/shamir.go
+package cmd
+
+import (
+	"fmt"
+)
+
+// Shamir's secret sharing over GF(2^8) with the AES polynomial
+// x^8 + x^4 + x^3 + x + 1. Each share is its x coordinate (1-255)
+// followed by one y value per secret byte.
+
+var gfExp, gfLog [256]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfLog[x] = byte(i)
+		// multiply by the generator 3
+		x ^= gfMulSlow(x, 2)
+	}
+	gfExp[255] = gfExp[0]
+}
+
+// gfMulSlow multiplies in GF(2^8) by shift-and-add; used to build the tables
+func gfMulSlow(a, b byte) byte {
+	var p byte
+	for b > 0 {
+		if b&1 == 1 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return p
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[(int(gfLog[a])+int(gfLog[b]))%255]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[(int(gfLog[a])-int(gfLog[b])+255)%255]
+}
+
+// shamirSplit splits secret into n shares, any k of which recover it
+func shamirSplit(secret []byte, n, k int) ([][]byte, error) {
+	if k < 2 || k > n || n > 255 {
+		return nil, fmt.Errorf("need 2 <= threshold <= shares <= 255, got threshold %d of %d", k, n)
+	}
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("secret cannot be empty")
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][0] = byte(i + 1)
+	}
+
+	coeffs := make([]byte, k)
+	for j, s := range secret {
+		// Random polynomial of degree k-1 whose constant term is the secret byte
+		coeffs[0] = s
+		random, err := genRandomBytes(k - 1)
+		if err != nil {
+			return nil, err
+		}
+		copy(coeffs[1:], random)
+
+		for i := range shares {
+			x := shares[i][0]
+			// Horner's rule
+			var y byte
+			for c := k - 1; c >= 0; c-- {
+				y = gfMul(y, x) ^ coeffs[c]
+			}
+			shares[i][j+1] = y
+		}
+	}
+	return shares, nil
+}
+
+// shamirCombine recovers the secret from shares by Lagrange interpolation at 0
+func shamirCombine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("need at least 2 shares, got: %d", len(shares))
+	}
+	size := len(shares[0])
+	seen := map[byte]bool{}
+	for _, s := range shares {
+		if len(s) != size || size < 2 {
+			return nil, fmt.Errorf("shares have inconsistent lengths")
+		}
+		if s[0] == 0 || seen[s[0]] {
+			return nil, fmt.Errorf("duplicate or invalid share index %d", s[0])
+		}
+		seen[s[0]] = true
+	}
+
+	secret := make([]byte, size-1)
+	for i, si := range shares {
+		// Lagrange basis polynomial for share i evaluated at x = 0
+		basis := byte(1)
+		for j, sj := range shares {
+			if i != j {
+				basis = gfMul(basis, gfDiv(sj[0], sj[0]^si[0]))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(si[b+1], basis)
+		}
+	}
+	return secret, nil
+}
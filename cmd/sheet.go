@@ -0,0 +1,217 @@
+// cmd/sheet.go
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+	"golang.org/x/term"
+)
+
+// sheetFormats are the printable formats accepted by --output on create set
+var sheetFormats = []string{"html", "pdf"}
+
+// sheetAnnotation marks commands that accept sheetFormats
+const sheetAnnotation = "keyforge.sheet"
+
+// sheetLegend explains the character styling used on printed sheets
+const sheetLegend = "Digits blue, symbols red, zero slashed. Spelling: lowercase word = lowercase letter, UPPERCASE word = capital."
+
+// natoAlphabet is the ICAO/NATO spelling alphabet
+var natoAlphabet = [26]string{
+	"alfa", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india",
+	"juliett", "kilo", "lima", "mike", "november", "oscar", "papa", "quebec", "romeo",
+	"sierra", "tango", "uniform", "victor", "whiskey", "x-ray", "yankee", "zulu",
+}
+
+// digitNames spells the digits 0-9
+var digitNames = [10]string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+
+// symbolNames spells the symbols keyforge passwords can contain
+var symbolNames = map[byte]string{
+	'!': "exclamation", '@': "at", '#': "hash", '$': "dollar", '%': "percent",
+	'^': "caret", '&': "ampersand", '*': "asterisk", '(': "open-paren", ')': "close-paren",
+	'-': "dash", '_': "underscore", '=': "equals", '+': "plus", '[': "open-bracket",
+	']': "close-bracket", '{': "open-brace", '}': "close-brace", ';': "semicolon",
+	':': "colon", ',': "comma", '.': "period", '<': "less-than", '>': "greater-than",
+	'/': "slash", '?': "question", '~': "tilde", '\\': "backslash", '|': "pipe",
+	'"': "quote", '\'': "apostrophe", '`': "backtick", ' ': "space",
+}
+
+// Sheet is a printable credential sheet
+type Sheet struct {
+	Title    string
+	Created  string
+	Legend   string
+	QR       bool
+	Sections []SheetSection
+}
+
+// SheetSection is one titled group of entries
+type SheetSection struct {
+	Title   string
+	Entries []SheetEntry
+}
+
+// SheetEntry is a single value with its spelling
+type SheetEntry struct {
+	Label    string
+	Value    string
+	Phonetic string
+}
+
+// SheetChar is one character of a value with its style class
+type SheetChar struct {
+	Char  string
+	Class string
+}
+
+// newPasswordSetSheet lays out a password set in the sections of printPasswordSet
+func newPasswordSetSheet(set *PasswordSet, title string, withQR bool) Sheet {
+	sheet := Sheet{
+		Title:   title,
+		Created: time.Now().UTC().Format("2006-01-02 15:04 MST"),
+		Legend:  sheetLegend,
+		QR:      withQR,
+	}
+	for _, section := range passwordSetSections(set) {
+		s := SheetSection{Title: section.name}
+		for i, v := range section.passwords {
+			s.Entries = append(s.Entries, SheetEntry{
+				Label:    fmt.Sprintf("%s %d", section.name, i+1),
+				Value:    v,
+				Phonetic: phoneticSpelling(v),
+			})
+		}
+		sheet.Sections = append(sheet.Sections, s)
+	}
+	return sheet
+}
+
+// phoneticSpelling spells s character by character, with capitals as
+// UPPERCASE NATO words
+func phoneticSpelling(s string) string {
+	words := make([]string, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			words = append(words, natoAlphabet[c-'a'])
+		case c >= 'A' && c <= 'Z':
+			words = append(words, strings.ToUpper(natoAlphabet[c-'A']))
+		case c >= '0' && c <= '9':
+			words = append(words, digitNames[c-'0'])
+		case symbolNames[c] != "":
+			words = append(words, symbolNames[c])
+		default:
+			words = append(words, string(c))
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// sheetCharClass returns "d" for digits, "s" for symbols and "" for letters
+func sheetCharClass(c byte) string {
+	switch {
+	case c >= '0' && c <= '9':
+		return "d"
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return ""
+	}
+	return "s"
+}
+
+// printSheet writes the sheet to w as HTML or PDF
+func printSheet(w io.Writer, sheet Sheet, format, paper string) error {
+	var data []byte
+	var err error
+	switch format {
+	case "html":
+		data, err = renderSheetHTML(sheet)
+	case "pdf":
+		if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			return fmt.Errorf("refusing to write a PDF to the terminal (use --out or redirect)")
+		}
+		data, err = renderSheetPDF(sheet, paper)
+	default:
+		return fmt.Errorf("unsupported sheet format %q (want html or pdf)", format)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+var sheetHTMLTemplate = template.Must(template.New("sheet").Funcs(template.FuncMap{
+	"chars": func(v string) []SheetChar {
+		chars := make([]SheetChar, len(v))
+		for i := 0; i < len(v); i++ {
+			chars[i] = SheetChar{Char: string(v[i]), Class: sheetCharClass(v[i])}
+		}
+		return chars
+	},
+	"qr": func(v string) (template.URL, error) {
+		png, err := qrcode.Encode(v, qrcode.Medium, 256)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode QR code: %w", err)
+		}
+		return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png)), nil
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 1.5cm; color: #000; }
+h1 { margin: 0 0 .2em; font-size: 20pt; }
+h2 { margin: 1.2em 0 .3em; font-size: 13pt; border-bottom: 2px solid #000; }
+.meta { font-size: 9pt; color: #444; margin: 0; }
+.entry { display: flex; gap: 1em; align-items: center; padding: .5em 0; border-bottom: 1px solid #ccc; break-inside: avoid; page-break-inside: avoid; }
+.label { font-size: 8pt; color: #666; }
+.value { font-family: "DejaVu Sans Mono", "Source Code Pro", "IBM Plex Mono", Consolas, monospace; font-variant-numeric: slashed-zero; font-size: 15pt; font-weight: bold; letter-spacing: .1em; word-break: break-all; }
+.value .d { color: #0645ad; }
+.value .s { color: #b00000; }
+.phonetic { font-size: 8.5pt; color: #333; margin-top: .2em; }
+.qr { width: 1in; height: 1in; image-rendering: pixelated; flex: none; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Generated {{.Created}}. {{.Legend}}</p>
+{{- range .Sections}}
+<section>
+<h2>{{.Title}}</h2>
+{{- range .Entries}}
+<div class="entry">
+{{- if $.QR}}<img class="qr" alt="QR code" src="{{qr .Value}}">{{end}}
+<div>
+<div class="label">{{.Label}}</div>
+<div class="value">{{range chars .Value}}{{if .Class}}<span class="{{.Class}}">{{.Char}}</span>{{else}}{{.Char}}{{end}}{{end}}</div>
+<div class="phonetic">{{.Phonetic}}</div>
+</div>
+</div>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// renderSheetHTML renders a self-contained printable HTML page
+func renderSheetHTML(sheet Sheet) ([]byte, error) {
+	var b bytes.Buffer
+	if err := sheetHTMLTemplate.Execute(&b, sheet); err != nil {
+		return nil, fmt.Errorf("failed to render sheet: %w", err)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,352 @@
+// cmd/split.go
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// shareChecksumLen is the number of SHA-256 bytes appended to a secret before
+// splitting, so combining wrong or too few shares is detected
+const shareChecksumLen = 4
+
+// SplitConfig holds configuration for splitting a secret into shares
+type SplitConfig struct {
+	Shares    int
+	Threshold int
+	Generate  string
+	Length    int
+	Encoding  string
+	OutDir    string
+	Force     bool
+	AsJSON    bool
+}
+
+// Share is one encoded share of a split secret
+type Share struct {
+	Index int    `json:"index"`
+	Value string `json:"value"`
+	Path  string `json:"path,omitempty"`
+}
+
+var splitCmd = &cobra.Command{
+	Use:   "split [secret]",
+	Short: "Split a secret into Shamir shares (k of n)",
+	Long: `Split a secret into --shares shares so that any --threshold of them recover
+it, using Shamir's secret sharing over GF(256).
+
+Use --generate to create the secret inside keyforge so it is never printed
+or written whole; only the shares are output. --out-dir writes one 0600
+file per share for handing to each custodian.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getSplitConfigFromFlags(cmd)
+
+		var secret string
+		var err error
+		if cfg.Generate != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("provide a secret or --generate, not both")
+			}
+			secret, err = genSplitSecret(cfg.Generate, cfg.Length)
+		} else {
+			secret, err = readSecretArg(cmd, args, "secret")
+		}
+		if err != nil {
+			return err
+		}
+
+		shares, err := splitSecret([]byte(secret), cfg)
+		if err != nil {
+			return fmt.Errorf("failed to split secret: %w", err)
+		}
+		if cfg.OutDir != "" {
+			if err := writeShareFiles(shares, cfg); err != nil {
+				return err
+			}
+		}
+		return printShares(cmd.OutOrStdout(), shares, cfg)
+	},
+}
+
+var combineCmd = &cobra.Command{
+	Use:   "combine [share...]",
+	Short: "Recover a secret from Shamir shares",
+	Long: `Recover a secret split by 'keyforge split'. Shares are given as arguments,
+one per line on STDIN (--stdin), or as files (--file, repeatable). The share
+encoding (hex, base64 or words) is detected automatically.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useStdin, _ := cmd.Flags().GetBool("stdin")
+		files, _ := cmd.Flags().GetStringSlice("file")
+		out, _ := cmd.Flags().GetString("out")
+		force, _ := cmd.Flags().GetBool("force")
+
+		inputs := append([]string{}, args...)
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return fmt.Errorf("failed to read share file: %w", err)
+			}
+			inputs = append(inputs, strings.TrimSpace(string(data)))
+		}
+		if useStdin {
+			s := bufio.NewScanner(os.Stdin)
+			for s.Scan() {
+				if line := strings.TrimSpace(s.Text()); line != "" {
+					inputs = append(inputs, line)
+				}
+			}
+			if err := s.Err(); err != nil {
+				return fmt.Errorf("failed to read shares: %w", err)
+			}
+		}
+
+		secret, err := combineShares(inputs)
+		if err != nil {
+			return err
+		}
+		if out != "" {
+			return writeSecretFile(out, secret, 0o600, force)
+		}
+		if utf8.Valid(secret) {
+			fmt.Println(string(secret))
+		} else {
+			fmt.Println(hex.EncodeToString(secret))
+		}
+		return nil
+	},
+}
+
+// getSplitConfigFromFlags extracts configuration from command flags
+func getSplitConfigFromFlags(cmd *cobra.Command) SplitConfig {
+	shares, _ := cmd.Flags().GetInt("shares")
+	threshold, _ := cmd.Flags().GetInt("threshold")
+	generate, _ := cmd.Flags().GetString("generate")
+	length, _ := cmd.Flags().GetInt("length")
+	encoding, _ := cmd.Flags().GetString("encoding")
+	outDir, _ := cmd.Flags().GetString("out-dir")
+	force, _ := cmd.Flags().GetBool("force")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	return SplitConfig{
+		Shares:    shares,
+		Threshold: threshold,
+		Generate:  strings.ToLower(generate),
+		Length:    length,
+		Encoding:  strings.ToLower(encoding),
+		OutDir:    outDir,
+		Force:     force,
+		AsJSON:    asJSON,
+	}
+}
+
+// genSplitSecret generates the secret to split without ever printing it
+func genSplitSecret(kind string, length int) (string, error) {
+	switch kind {
+	case "strong":
+		return genStrongWithError(length)
+	case "easy":
+		return genEasyWithError(length)
+	case "key":
+		return genKeyWithError(256, "base64")
+	default:
+		return "", fmt.Errorf("unsupported --generate %q (want strong, easy or key)", kind)
+	}
+}
+
+// splitSecret appends a checksum, splits and encodes the secret
+func splitSecret(secret []byte, cfg SplitConfig) ([]Share, error) {
+	sum := sha256.Sum256(secret)
+	payload := append(append([]byte{}, secret...), sum[:shareChecksumLen]...)
+
+	raw, err := shamirSplit(payload, cfg.Shares, cfg.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share, len(raw))
+	for i, r := range raw {
+		v, err := encodeShare(r, cfg.Encoding)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = Share{Index: int(r[0]), Value: v}
+	}
+	return shares, nil
+}
+
+// combineShares decodes shares, interpolates and verifies the checksum
+func combineShares(inputs []string) ([]byte, error) {
+	raw := make([][]byte, 0, len(inputs))
+	for _, in := range inputs {
+		r, err := decodeShare(in)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, r)
+	}
+
+	payload, err := shamirCombine(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) <= shareChecksumLen {
+		return nil, fmt.Errorf("shares are too short to hold a secret")
+	}
+	secret, check := payload[:len(payload)-shareChecksumLen], payload[len(payload)-shareChecksumLen:]
+	sum := sha256.Sum256(secret)
+	if !bytes.Equal(sum[:shareChecksumLen], check) {
+		return nil, fmt.Errorf("checksum mismatch: not enough shares, or shares from different secrets")
+	}
+	return secret, nil
+}
+
+// encodeShare renders a share as hex, base64 or BIP39 words
+func encodeShare(share []byte, encoding string) (string, error) {
+	switch encoding {
+	case "hex":
+		return hex.EncodeToString(share), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(share), nil
+	case "words":
+		if len(share) > 255 {
+			return "", fmt.Errorf("secret is too long for word encoding")
+		}
+		list, _, err := bip39Wordlist("english")
+		if err != nil {
+			return "", err
+		}
+		// A leading length byte lets the decoder drop the 11-bit padding
+		data := append([]byte{byte(len(share))}, share...)
+		var words []string
+		var acc uint32
+		var bits uint
+		for _, b := range data {
+			acc = acc<<8 | uint32(b)
+			bits += 8
+			for bits >= 11 {
+				bits -= 11
+				words = append(words, list[acc>>bits&0x7ff])
+			}
+		}
+		if bits > 0 {
+			words = append(words, list[acc<<(11-bits)&0x7ff])
+		}
+		return strings.Join(words, " "), nil
+	default:
+		return "", fmt.Errorf("unsupported share encoding %q (want hex, base64 or words)", encoding)
+	}
+}
+
+// decodeShare detects and decodes a hex, base64 or word-encoded share
+func decodeShare(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, " ") {
+		_, index, err := bip39Wordlist("english")
+		if err != nil {
+			return nil, err
+		}
+		var acc uint32
+		var bits uint
+		var data []byte
+		for _, w := range strings.Fields(strings.ToLower(s)) {
+			idx, ok := index[w]
+			if !ok {
+				return nil, fmt.Errorf("unknown word %q in share", w)
+			}
+			acc = acc<<11 | uint32(idx)
+			bits += 11
+			for bits >= 8 {
+				bits -= 8
+				data = append(data, byte(acc>>bits))
+			}
+		}
+		if len(data) == 0 || int(data[0]) > len(data)-1 {
+			return nil, fmt.Errorf("malformed word share")
+		}
+		return data[1 : 1+int(data[0])], nil
+	}
+	if b, err := hex.DecodeString(s); err == nil {
+		return b, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("share is not valid hex, base64 or words")
+	}
+	return b, nil
+}
+
+// writeShareFiles writes each share to its own 0600 file in cfg.OutDir
+func writeShareFiles(shares []Share, cfg SplitConfig) error {
+	if err := os.MkdirAll(cfg.OutDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create share directory: %w", err)
+	}
+	for i := range shares {
+		shares[i].Path = filepath.Join(cfg.OutDir, fmt.Sprintf("share-%d-of-%d.txt", shares[i].Index, cfg.Shares))
+	}
+	// Check every name first so a conflict does not leave a partial set
+	for _, s := range shares {
+		if err := checkOverwrite(s.Path, cfg.Force); err != nil {
+			return err
+		}
+	}
+	for _, s := range shares {
+		if err := writeSecretFile(s.Path, []byte(s.Value+"\n"), 0o600, cfg.Force); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printShares outputs the shares (or their file paths) in the requested format
+func printShares(w io.Writer, shares []Share, cfg SplitConfig) error {
+	if cfg.OutDir != "" {
+		for i := range shares {
+			shares[i].Value = ""
+		}
+	}
+	if cfg.AsJSON {
+		return printJSON(w, shares, "shares")
+	}
+
+	fmt.Fprintf(w, "Any %d of %d shares recover the secret.\n\n", cfg.Threshold, cfg.Shares)
+	for _, s := range shares {
+		if s.Path != "" {
+			fmt.Fprintf(w, "Share %d: %s\n", s.Index, s.Path)
+		} else {
+			fmt.Fprintf(w, "Share %d: %s\n", s.Index, s.Value)
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+	rootCmd.AddCommand(combineCmd)
+
+	splitCmd.Flags().IntP("shares", "n", 5, "number of shares to create")
+	splitCmd.Flags().IntP("threshold", "k", 3, "number of shares needed to recover the secret")
+	splitCmd.Flags().StringP("generate", "g", "", "generate the secret instead of reading it (strong|easy|key)")
+	splitCmd.Flags().IntP("length", "l", 32, "length of a generated strong/easy secret")
+	splitCmd.Flags().StringP("encoding", "e", "hex", "share encoding (hex|base64|words)")
+	splitCmd.Flags().String("out-dir", "", "write each share to its own 0600 file in this directory")
+	splitCmd.Flags().Bool("force", false, "overwrite existing share files")
+	splitCmd.Flags().Bool("stdin", false, "read the secret from STDIN")
+	splitCmd.Flags().Bool("json", false, "output as JSON")
+
+	combineCmd.Flags().Bool("stdin", false, "read shares from STDIN, one per line")
+	combineCmd.Flags().StringSlice("file", nil, "read a share from this file (repeatable)")
+	combineCmd.Flags().StringP("out", "o", "", "write the recovered secret to this file with 0600 permissions")
+	combineCmd.Flags().Bool("force", false, "overwrite an existing --out file")
+}
@@ -0,0 +1,170 @@
+// cmd/stats.go
+package cmd
+
+import (
+	"math"
+)
+
+// chiSquared computes the chi-squared statistic for observed counts against a
+// uniform distribution and returns the statistic with its p-value
+func chiSquared(counts []int) (float64, float64) {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	k := len(counts)
+	if k < 2 || total == 0 {
+		return 0, 1
+	}
+
+	expected := float64(total) / float64(k)
+	stat := 0.0
+	for _, c := range counts {
+		d := float64(c) - expected
+		stat += d * d / expected
+	}
+	return stat, igamc(float64(k-1)/2, stat/2)
+}
+
+// serialCorrelation computes Knuth's serial correlation coefficient for a
+// sequence of values and returns it with a two-sided p-value
+func serialCorrelation(values []float64) (float64, float64) {
+	n := float64(len(values))
+	if n < 4 {
+		return 0, 1
+	}
+
+	var sum, sumSq, sumLag float64
+	for i, v := range values {
+		sum += v
+		sumSq += v * v
+		sumLag += v * values[(i+1)%len(values)]
+	}
+	denom := n*sumSq - sum*sum
+	if denom == 0 {
+		return 0, 1
+	}
+	c := (n*sumLag - sum*sum) / denom
+
+	// Under the null hypothesis C is approximately normal (Knuth, TAOCP 3.3.2)
+	mu := -1 / (n - 1)
+	sigma := math.Sqrt(n*(n-3)/(n+1)) / (n - 1)
+	return c, math.Erfc(math.Abs(c-mu) / sigma / math.Sqrt2)
+}
+
+// monobitTest is the NIST SP 800-22 frequency (monobit) test
+func monobitTest(bits []byte) (float64, float64) {
+	if len(bits) == 0 {
+		return 0, 1
+	}
+	s := 0
+	for _, b := range bits {
+		if b == 1 {
+			s++
+		} else {
+			s--
+		}
+	}
+	sObs := math.Abs(float64(s)) / math.Sqrt(float64(len(bits)))
+	return sObs, math.Erfc(sObs / math.Sqrt2)
+}
+
+// runsTest is the NIST SP 800-22 runs test. If the monobit prerequisite
+// fails, the p-value is 0 as the specification requires.
+func runsTest(bits []byte) (float64, float64) {
+	n := float64(len(bits))
+	if n < 2 {
+		return 0, 1
+	}
+	ones := 0
+	for _, b := range bits {
+		ones += int(b)
+	}
+	pi := float64(ones) / n
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(n) {
+		return 0, 0
+	}
+
+	runs := 1
+	for i := 1; i < len(bits); i++ {
+		if bits[i] != bits[i-1] {
+			runs++
+		}
+	}
+	v := float64(runs)
+	p := math.Erfc(math.Abs(v-2*n*pi*(1-pi)) / (2 * math.Sqrt(2*n) * pi * (1 - pi)))
+	return v, p
+}
+
+// bytesToBits expands bytes into a slice of 0/1 values, most significant bit first
+func bytesToBits(data []byte) []byte {
+	bits := make([]byte, 0, len(data)*8)
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, (b>>uint(i))&1)
+		}
+	}
+	return bits
+}
+
+// igamc computes the regularized upper incomplete gamma function Q(a, x)
+func igamc(a, x float64) float64 {
+	if x <= 0 || a <= 0 {
+		return 1
+	}
+	if x < a+1 {
+		return 1 - igamSeries(a, x)
+	}
+	return igamContinuedFraction(a, x)
+}
+
+const (
+	igamMaxIter = 1000
+	igamEpsilon = 1e-14
+)
+
+// igamSeries evaluates the lower regularized gamma P(a, x) by its series expansion
+func igamSeries(a, x float64) float64 {
+	lg, _ := math.Lgamma(a)
+	sum := 1 / a
+	del := sum
+	ap := a
+	for i := 0; i < igamMaxIter; i++ {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*igamEpsilon {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-lg)
+}
+
+// igamContinuedFraction evaluates Q(a, x) by Lentz's continued fraction
+func igamContinuedFraction(a, x float64) float64 {
+	const tiny = 1e-300
+	lg, _ := math.Lgamma(a)
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i <= igamMaxIter; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < igamEpsilon {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lg) * h
+}
@@ -0,0 +1,261 @@
+// cmd/unique.go
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	bolt "go.etcd.io/bbolt"
+)
+
+// maxUniqueRetries bounds consecutive duplicate draws before giving up
+const maxUniqueRetries = 1000
+
+var (
+	ledgerBucket = []byte("issued")
+	// ledgerMetaBucket holds the ledger's own HMAC key under ledgerKeyName
+	ledgerMetaBucket = []byte("meta")
+	ledgerKeyName    = []byte("hmac-key")
+)
+
+// uniqueSet tracks values already seen in this batch, in an --against file
+// or in a --ledger database
+type uniqueSet struct {
+	seen      map[string]struct{}
+	ledger    *bolt.DB
+	ledgerMAC []byte
+}
+
+// newUniqueSet loads the optional exclusion file and opens the optional ledger
+func newUniqueSet(againstPath, ledgerPath string) (*uniqueSet, error) {
+	u := &uniqueSet{seen: make(map[string]struct{})}
+
+	if againstPath != "" {
+		f, err := os.Open(againstPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open exclusion file: %w", err)
+		}
+		defer f.Close()
+
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			if line := strings.TrimSpace(s.Text()); line != "" {
+				u.seen[line] = struct{}{}
+			}
+		}
+		if err := s.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read exclusion file: %w", err)
+		}
+	}
+
+	if ledgerPath != "" {
+		db, err := bolt.Open(ledgerPath, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ledger: %w", err)
+		}
+		err = db.Update(func(tx *bolt.Tx) error {
+			if _, err := tx.CreateBucketIfNotExists(ledgerBucket); err != nil {
+				return err
+			}
+			meta, err := tx.CreateBucketIfNotExists(ledgerMetaBucket)
+			if err != nil {
+				return err
+			}
+			// bbolt values are only valid inside the transaction
+			if key := meta.Get(ledgerKeyName); key != nil {
+				u.ledgerMAC = append([]byte{}, key...)
+				return nil
+			}
+			if u.ledgerMAC, err = genRandomBytes(32); err != nil {
+				return err
+			}
+			return meta.Put(ledgerKeyName, u.ledgerMAC)
+		})
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to initialise ledger: %w", err)
+		}
+		u.ledger = db
+	}
+
+	return u, nil
+}
+
+// Close releases the ledger, if one is open
+func (u *uniqueSet) Close() error {
+	if u.ledger == nil {
+		return nil
+	}
+	return u.ledger.Close()
+}
+
+// contains reports whether v was already issued
+func (u *uniqueSet) contains(v string) (bool, error) {
+	if _, ok := u.seen[v]; ok {
+		return true, nil
+	}
+	if u.ledger == nil {
+		return false, nil
+	}
+
+	found := false
+	key := u.ledgerKey(v)
+	err := u.ledger.View(func(tx *bolt.Tx) error {
+		found = tx.Bucket(ledgerBucket).Get(key) != nil
+		return nil
+	})
+	return found, err
+}
+
+// add marks v as seen for the rest of the batch
+func (u *uniqueSet) add(v string) {
+	u.seen[v] = struct{}{}
+}
+
+// record stores the batch in the ledger in a single transaction
+func (u *uniqueSet) record(values []string) error {
+	if u.ledger == nil {
+		return nil
+	}
+	issued := []byte(time.Now().UTC().Format(time.RFC3339))
+	return u.ledger.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(ledgerBucket)
+		for _, v := range values {
+			if err := b.Put(u.ledgerKey(v), issued); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ledgerKey is an HMAC-SHA-256 of v under the ledger's random key. Entries
+// cannot be matched against precomputed tables or other ledgers, but the
+// key lives in the same file, so the ledger must be kept as private as the
+// values it records.
+func (u *uniqueSet) ledgerKey(v string) []byte {
+	mac := hmac.New(sha256.New, u.ledgerMAC)
+	mac.Write([]byte(v))
+	return mac.Sum(nil)
+}
+
+// collisionProbability approximates the birthday-collision probability of
+// drawing n values uniformly from a keyspace of 2^bits
+func collisionProbability(n int, bits float64) float64 {
+	if n < 2 {
+		return 0
+	}
+	pairs := float64(n) * float64(n-1) / 2
+	return -math.Expm1(-pairs / math.Exp2(bits))
+}
+
+// ledgerBatchKey is the context key of the ledgerBatch set up by withLedger
+type ledgerBatchKey struct{}
+
+// ledgerBatch is a batch waiting to be recorded in the ledger
+type ledgerBatch struct {
+	set    *uniqueSet
+	values []string
+}
+
+// finish records the batch unless err is set, then closes the ledger
+func (b *ledgerBatch) finish(err error) error {
+	if b.set == nil {
+		return err
+	}
+	defer b.set.Close()
+	if err != nil || b.values == nil {
+		return err
+	}
+	if err := b.set.record(b.values); err != nil {
+		return fmt.Errorf("failed to record ledger: %w", err)
+	}
+	return nil
+}
+
+// withLedger wraps a create command so that a --ledger batch is recorded
+// only after the command, including --out and encryption, has succeeded
+func withLedger(run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		batch := &ledgerBatch{}
+		cmd.SetContext(context.WithValue(ctx, ledgerBatchKey{}, batch))
+		defer cmd.SetContext(ctx)
+		return batch.finish(run(cmd, args))
+	}
+}
+
+// generateBatch runs gen cfg.Count times. With --unique, --against or
+// --ledger it reports the collision odds and rejects repeats; under
+// withLedger the batch is recorded once the output has been written.
+func generateBatch(cmd *cobra.Command, cfg Config, bits float64, gen func() (string, error)) (_ []string, err error) {
+	results := make([]string, 0, cfg.Count)
+
+	if !cfg.Unique {
+		for i := 0; i < cfg.Count; i++ {
+			v, err := gen()
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, v)
+		}
+		return results, nil
+	}
+
+	if math.Exp2(bits) < float64(cfg.Count) {
+		return nil, fmt.Errorf("keyspace of 2^%.1f is too small for %d unique values", bits, cfg.Count)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Collision probability for %d values over 2^%.1f: %.3g\n",
+		cfg.Count, bits, collisionProbability(cfg.Count, bits))
+
+	u, err := newUniqueSet(cfg.Against, cfg.Ledger)
+	if err != nil {
+		return nil, err
+	}
+	var batch *ledgerBatch
+	if ctx := cmd.Context(); ctx != nil {
+		batch, _ = ctx.Value(ledgerBatchKey{}).(*ledgerBatch)
+	}
+	if batch == nil {
+		// Not wrapped by withLedger: record as soon as the batch is complete
+		batch = &ledgerBatch{}
+		defer func() { err = batch.finish(err) }()
+	}
+	batch.set = u
+
+	retries := 0
+	for len(results) < cfg.Count {
+		v, err := gen()
+		if err != nil {
+			return nil, err
+		}
+		dup, err := u.contains(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query ledger: %w", err)
+		}
+		if dup {
+			retries++
+			if retries >= maxUniqueRetries {
+				return nil, fmt.Errorf("gave up after %d consecutive duplicates; keyspace is nearly exhausted", retries)
+			}
+			continue
+		}
+		retries = 0
+		u.add(v)
+		results = append(results, v)
+	}
+
+	batch.values = results
+	return results, nil
+}